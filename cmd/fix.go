@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dashu-baba/docker-doctor/internal/collector"
+	"github.com/dashu-baba/docker-doctor/internal/remediate"
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+)
+
+// fixCmd represents the fix command
+var fixCmd = &cobra.Command{
+	Use:   "fix",
+	Short: "Apply the typed remediation actions of a scan (dry-run by default)",
+	Long: `Turn the recommendations of a v1 scan into typed actions executed through the
+Docker API: remove specific images, prune build cache older than a cutoff, remove
+unused volumes by name, and truncate/rotate json-file container logs.
+
+Nothing is changed unless --apply is given. Only actions whose recommendation risk
+is at or below --allow (safe < planned < risky) are selected, each applied action
+is confirmed interactively (unless --yes), re-checked afterwards, and recorded in
+the audit log.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scanFile, _ := cmd.Flags().GetString("scan")
+		allow, _ := cmd.Flags().GetString("allow")
+		apply, _ := cmd.Flags().GetBool("apply")
+		yes, _ := cmd.Flags().GetBool("yes")
+		auditLog, _ := cmd.Flags().GetString("audit-log")
+		apiVersion, _ := cmd.Flags().GetString("api-version")
+		return runFix(scanFile, allow, apply, yes, auditLog, apiVersion, os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fixCmd)
+
+	fixCmd.Flags().String("scan", "scan.json", "v1 scan.json to take actions from")
+	fixCmd.Flags().String("allow", "safe", "Highest recommendation risk to act on: safe, planned or risky")
+	fixCmd.Flags().Bool("apply", false, "Execute the actions (default is a dry-run)")
+	fixCmd.Flags().Bool("yes", false, "Do not ask for confirmation before each action")
+	fixCmd.Flags().String("audit-log", "docker-doctor-fix.log", "JSON-lines audit log of executed actions")
+	fixCmd.Flags().String("api-version", "", "Docker API version to use (overrides config)")
+}
+
+func runFix(scanFile, allow string, apply, yes bool, auditLog, apiVersion string, in io.Reader, out io.Writer) error {
+	if !remediate.ValidRisk(allow) {
+		return ExitError{Code: 3, Err: fmt.Errorf("invalid --allow %q (use safe, planned or risky)", allow)}
+	}
+	data, err := os.ReadFile(scanFile)
+	if err != nil {
+		return ExitError{Code: 3, Err: fmt.Errorf("failed to read scan file: %w", err)}
+	}
+	if detectSchemaVersion(data) != "1.0" {
+		return ExitError{Code: 3, Err: fmt.Errorf("fix requires a v1 scan.json")}
+	}
+	var report v1.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return ExitError{Code: 3, Err: fmt.Errorf("failed to unmarshal v1 JSON: %w", err)}
+	}
+
+	steps, held := remediate.Plan(&report, allow)
+	for _, st := range held {
+		fmt.Fprintf(out, "skip  [%s] %s (risk %s exceeds --allow %s)\n", st.FindingID, st.Describe(), st.Risk, allow)
+	}
+	if len(steps) == 0 {
+		fmt.Fprintln(out, "No actions to run.")
+		return nil
+	}
+
+	if !apply {
+		ex := &remediate.Executor{}
+		for _, st := range steps {
+			res := ex.Execute(context.Background(), st, true)
+			fmt.Fprintf(out, "plan  [%s] %s (risk %s)\n", st.FindingID, res.Detail, st.Risk)
+		}
+		fmt.Fprintln(out, "Dry-run: nothing was changed. Re-run with --apply to execute.")
+		return nil
+	}
+
+	cfg, err := loadScanConfig()
+	if err != nil {
+		return ExitError{Code: 3, Err: err}
+	}
+	if apiVersion == "" {
+		apiVersion = cfg.Scan.Version
+	}
+	cli, err := collector.NewClient(cfg.Scan.DockerHost, apiVersion)
+	if err != nil {
+		return ExitError{Code: 3, Err: fmt.Errorf("failed to create Docker client: %w", err)}
+	}
+	defer cli.Close()
+
+	audit, closer, err := remediate.OpenAuditLog(auditLog)
+	if err != nil {
+		return ExitError{Code: 3, Err: fmt.Errorf("failed to open audit log: %w", err)}
+	}
+	defer closer.Close()
+
+	return applySteps(&remediate.Executor{Docker: cli}, audit, steps, yes, time.Duration(cfg.Scan.Timeout)*time.Second, in, out)
+}
+
+// applySteps executes steps one by one, asking for confirmation unless yes is set.
+// It returns an error when any step failed.
+func applySteps(ex *remediate.Executor, audit *remediate.AuditLog, steps []remediate.Step, yes bool, timeout time.Duration, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	failed := 0
+	for _, st := range steps {
+		if !yes && !confirm(reader, out, fmt.Sprintf("[%s] %s (risk %s)", st.FindingID, st.Describe(), st.Risk)) {
+			res := remediate.Result{Time: time.Now().UTC(), Step: st, Status: "declined"}
+			if err := audit.Record(res); err != nil {
+				return ExitError{Code: 3, Err: fmt.Errorf("failed to write audit log: %w", err)}
+			}
+			fmt.Fprintf(out, "skip  %s\n", st.Describe())
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		res := ex.Execute(ctx, st, false)
+		cancel()
+		if err := audit.Record(res); err != nil {
+			return ExitError{Code: 3, Err: fmt.Errorf("failed to write audit log: %w", err)}
+		}
+		if res.Status == "failed" {
+			failed++
+			fmt.Fprintf(out, "FAIL  %s: %s\n", st.Describe(), res.Error)
+			continue
+		}
+		fmt.Fprintf(out, "done  %s: %s (%s)\n", st.Describe(), res.Detail, res.Recheck)
+	}
+	if failed > 0 {
+		return ExitError{Code: 3, Err: fmt.Errorf("%d action(s) failed", failed)}
+	}
+	return nil
+}
+
+func confirm(r *bufio.Reader, out io.Writer, prompt string) bool {
+	fmt.Fprintf(out, "%s\nApply? [y/N] ", prompt)
+	line, err := r.ReadString('\n')
+	if err != nil && line == "" {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
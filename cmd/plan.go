@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dashu-baba/docker-doctor/internal/collector"
+	"github.com/dashu-baba/docker-doctor/internal/plan"
+)
+
+// planCmd represents the plan command
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Estimate reclaimable space per prune operation and emit a cleanup script",
+	Long: `Simulate Docker prune operations against the full /system/df payload and show
+how many bytes each would free. Operations run in a fixed order, and each estimate
+accounts for the ones before it (e.g. images only used by stopped containers become
+prunable after container-prune).
+
+Operations (--ops, comma-separated):
+  container-prune   docker container prune            (planned)
+  image-prune       docker image prune                (safe)
+  image-prune-all   docker image prune -a             (planned)
+  builder-prune     docker builder prune --filter until=<--until>  (safe)
+  volume-prune      docker volume prune (anonymous)   (risky)
+  volume-prune-all  docker volume prune -a            (risky)
+
+Nothing is executed; use --script to write the plan as a shell script.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ops, _ := cmd.Flags().GetString("ops")
+		until, _ := cmd.Flags().GetDuration("until")
+		script, _ := cmd.Flags().GetString("script")
+		apiVersion, _ := cmd.Flags().GetString("api-version")
+		return runPlan(ops, until, script, apiVersion, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+
+	planCmd.Flags().String("ops", strings.Join(plan.DefaultOperations, ","), "Comma-separated operations to include: "+strings.Join(plan.Operations, ","))
+	planCmd.Flags().Duration("until", 168*time.Hour, "Only prune build cache unused for longer than this (0 = all unused)")
+	planCmd.Flags().String("script", "", "Write the plan as a shell script to this path (- for stdout)")
+	planCmd.Flags().String("api-version", "", "Docker API version to use (overrides config)")
+}
+
+func runPlan(opsFlag string, until time.Duration, script string, apiVersion string, out io.Writer) error {
+	ops, err := plan.ParseOperations(opsFlag)
+	if err != nil {
+		return ExitError{Code: 3, Err: err}
+	}
+	if until < 0 {
+		return ExitError{Code: 3, Err: fmt.Errorf("--until must not be negative")}
+	}
+
+	cfg, err := loadScanConfig()
+	if err != nil {
+		return ExitError{Code: 3, Err: err}
+	}
+	if apiVersion == "" {
+		apiVersion = cfg.Scan.Version
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Scan.Timeout)*time.Second)
+	defer cancel()
+	df, err := collector.CollectDockerSystemDfSummary(ctx, cfg.Scan.DockerHost, apiVersion)
+	if err != nil {
+		return ExitError{Code: 3, Err: fmt.Errorf("failed to read Docker disk usage: %w", err)}
+	}
+
+	p := plan.Simulate(df, ops, until, time.Now())
+	if script == "-" {
+		fmt.Fprint(out, p.Script())
+		return nil
+	}
+
+	writePlanTable(out, p)
+	if script != "" {
+		if err := os.WriteFile(script, []byte(p.Script()), 0o755); err != nil {
+			return ExitError{Code: 3, Err: fmt.Errorf("failed to write script: %w", err)}
+		}
+		fmt.Fprintf(out, "\nScript written to %s\n", script)
+	}
+	return nil
+}
+
+func writePlanTable(out io.Writer, p *plan.Plan) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STEP\tOPERATION\tRISK\tOBJECTS\tRECLAIMABLE")
+	for i, s := range p.Steps {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\n", i+1, s.Name, s.Risk, s.Objects, humanBytes(s.ReclaimableBytes))
+	}
+	fmt.Fprintf(tw, "\tTOTAL\t\t\t%s\n", humanBytes(p.TotalReclaimableBytes))
+	_ = tw.Flush()
+	fmt.Fprintln(out, "\nImage estimates count unique layers only; shared layers are freed once no remaining image uses them.")
+}
@@ -33,7 +33,7 @@ func init() {
 	rootCmd.AddCommand(reportCmd)
 
 	reportCmd.Flags().StringP("input", "i", "scan.json", "Input JSON file from scan")
-	reportCmd.Flags().StringP("format", "f", "html", "Output format: html or md")
+	reportCmd.Flags().StringP("format", "f", "html", "Output format: html, md, gitlab (Code Quality JSON) or github (Actions annotations)")
 	reportCmd.Flags().StringP("output", "o", "", "Output file (default stdout)")
 }
 
@@ -56,6 +56,10 @@ func runReport(input, format, output string) error {
 			result, err = generateHTMLv1(&reportV1)
 		case "md":
 			result, err = generateMarkdownv1(&reportV1)
+		case "gitlab":
+			result, err = generateGitLabCodeQualityv1(&reportV1)
+		case "github":
+			result, err = generateGitHubAnnotationsv1(&reportV1)
 		default:
 			return fmt.Errorf("unsupported format: %s", format)
 		}
@@ -104,6 +108,9 @@ func generateHTMLv1(report *v1.Report) (string, error) {
 		"title":     strings.Title,
 		"sevClass":  severityClass,
 		"riskClass": riskClass,
+		"pct": func(ratio float64) string {
+			return fmt.Sprintf("%.0f%%", ratio*100)
+		},
 		"json": func(v interface{}) string {
 			b, _ := json.Marshal(v)
 			return string(b)
@@ -218,6 +225,7 @@ func generateHTMLv1(report *v1.Report) (string, error) {
         {{if .Target.Host.Kernel}}<div class="kv">Kernel: {{.Target.Host.Kernel}}</div>{{end}}
         {{if gt .Target.Host.UptimeSeconds 0}}<div class="kv">Uptime: {{.Target.Host.UptimeSeconds}}s</div>{{end}}
         {{if .Target.Docker.CgroupVersion}}<div class="kv">Cgroup Version: {{.Target.Docker.CgroupVersion}}</div>{{end}}
+        {{if .Target.Docker.CgroupDriver}}<div class="kv">Cgroup Driver: {{.Target.Docker.CgroupDriver}}</div>{{end}}
         {{if .Target.Docker.DataRoot}}<div class="kv">Data Root: {{.Target.Docker.DataRoot}}</div>{{end}}
       </div>
       <div class="card">
@@ -246,6 +254,22 @@ func generateHTMLv1(report *v1.Report) (string, error) {
           <div class="kv">Volumes: <strong>{{bytes .Summary.ResourceSnapshot.DockerSystemDf.VolumesTotalBytes}}</strong></div>
           <div class="kv">Containers writable: <strong>{{bytes .Summary.ResourceSnapshot.DockerSystemDf.ContainersWritableTotalBytes}}</strong></div>
         </div>
+        {{with .Summary.ResourceSnapshot.Filesystems}}
+        <div class="card">
+          <h3>Filesystems</h3>
+          {{range .}}
+          <div class="kv"><code>{{.Path}}</code>: <strong>{{printf "%.1f" .UsedPercent}}%</strong> of {{bytes .TotalBytes}}{{if gt .InodesTotal 0}} · inodes <strong>{{printf "%.1f" .InodesUsedPercent}}%</strong>{{end}}{{if .MountPoint}} · {{.FSType}} on <code>{{.MountPoint}}</code>{{end}}{{if .SameDevicePaths}} · also {{range $i, $p := .SameDevicePaths}}{{if $i}}, {{end}}<code>{{$p}}</code>{{end}}{{end}}</div>
+          {{end}}
+        </div>
+        {{end}}
+        {{with .Summary.ResourceSnapshot.Limits}}
+        <div class="card">
+          <h3>Container limits vs host</h3>
+          <div class="kv">Memory: <strong>{{bytes .MemoryLimitBytes}}</strong> of {{bytes .HostMemoryBytes}} (<strong>{{pct .MemoryOvercommitRatio}}</strong>)</div>
+          <div class="kv">CPU: <strong>{{printf "%.2f" .CPULimitCores}}</strong> of {{.HostCPUs}} CPUs (<strong>{{pct .CPUOvercommitRatio}}</strong>)</div>
+          <div class="kv">Running without memory limit: <strong>{{.ContainersWithoutMemoryLimit}}</strong> · without CPU limit: <strong>{{.ContainersWithoutCPULimit}}</strong></div>
+        </div>
+        {{end}}
         <div class="card">
           <h3>Capabilities</h3>
           <div class="kv">Docker API: <strong>{{.Scan.Capabilities.DockerAPI}}</strong></div>
@@ -256,6 +280,61 @@ func generateHTMLv1(report *v1.Report) (string, error) {
       </div>
     </div>
 
+    {{if .CleanupPlan}}
+    <div class="section">
+      <h2>Cleanup plan</h2>
+      <div class="kv">Space each prune operation would free on its own (builder prune: unused for {{.CleanupPlan.BuilderPruneUntil}}). Combine them with <code>docker-doctor plan</code>.</div>
+      <table>
+        <tr><th>Operation</th><th>Risk</th><th>Objects</th><th>Reclaimable</th><th>Command</th></tr>
+        {{range .CleanupPlan.Operations}}
+        <tr>
+          <td>{{.Description}}</td>
+          <td><span class="badge {{riskClass .Risk}}">{{.Risk}}</span></td>
+          <td class="muted">{{.Objects}}</td>
+          <td><strong>{{bytes .ReclaimableBytes}}</strong></td>
+          <td><code>{{.Command}}</code></td>
+        </tr>
+        {{end}}
+      </table>
+    </div>
+    {{end}}
+
+    {{with .Inventory}}{{if .PublishedPorts}}
+    <div class="section">
+      <h2>Published ports</h2>
+      <table>
+        <tr><th>Container</th><th>Host address</th><th>Host port</th><th>Container port</th><th>Exposure</th><th>Service</th></tr>
+        {{range .PublishedPorts}}
+        <tr>
+          <td><code>{{.ContainerName}}</code></td>
+          <td class="muted"><code>{{if .HostIP}}{{.HostIP}}{{else}}*{{end}}</code></td>
+          <td>{{.HostPort}}</td>
+          <td class="muted">{{.ContainerPort}}/{{.Protocol}}</td>
+          <td>{{if .Public}}<span class="badge {{if .Service}}critical{{else}}warning{{end}}">all interfaces</span>{{else}}<span class="muted">restricted</span>{{end}}</td>
+          <td class="muted">{{if .Service}}{{.Service}}{{else}}-{{end}}</td>
+        </tr>
+        {{end}}
+      </table>
+    </div>
+    {{end}}{{end}}
+
+    {{with .Inventory}}{{if .LogPolicies}}
+    <div class="section">
+      <h2>Log policy</h2>
+      <table>
+        <tr><th>Container</th><th>Driver</th><th>Retention</th><th>Log size</th></tr>
+        {{range .LogPolicies}}
+        <tr>
+          <td><code>{{.ContainerName}}</code></td>
+          <td class="muted">{{.Driver}}</td>
+          <td>{{if .Bounded}}{{.Policy}}{{else}}<span class="badge warning">{{.Policy}}</span>{{end}}</td>
+          <td class="muted">{{if gt .LogSizeBytes 0}}{{bytes .LogSizeBytes}}{{else}}-{{end}}</td>
+        </tr>
+        {{end}}
+      </table>
+    </div>
+    {{end}}{{end}}
+
     <div class="section">
       <h2>Collectors</h2>
       <table>
@@ -343,6 +422,14 @@ func generateHTMLv1(report *v1.Report) (string, error) {
                 <div class="muted" style="margin-top:10px;">No recommendations provided.</div>
               {{end}}
             </details>
+            {{if .References}}
+            <details>
+              <summary>References</summary>
+              <ul class="muted" style="margin-top:10px; padding-left:20px;">
+                {{range .References}}<li>{{if .URL}}<a href="{{.URL}}">{{.Label}}</a>{{else}}{{.Label}}{{end}}</li>{{end}}
+              </ul>
+            </details>
+            {{end}}
           </div>
         {{end}}
       {{else}}
@@ -379,6 +466,7 @@ func generateMarkdownv1(report *v1.Report) (string, error) {
 - **Kernel:** %s
 - **Uptime:** %d s
 - **Cgroup Version:** %s
+- **Cgroup Driver:** %s
 - **Data Root:** %s
 
 ## Summary
@@ -415,6 +503,7 @@ func generateMarkdownv1(report *v1.Report) (string, error) {
 		report.Target.Host.Kernel,
 		report.Target.Host.UptimeSeconds,
 		report.Target.Docker.CgroupVersion,
+		report.Target.Docker.CgroupDriver,
 		report.Target.Docker.DataRoot,
 		report.Summary.Counts.ContainersRunning,
 		report.Summary.Counts.ContainersStopped,
@@ -437,6 +526,63 @@ func generateMarkdownv1(report *v1.Report) (string, error) {
 		md += fmt.Sprintf("| `%s` | %s | %dms | %s |\n", c.Name, c.Status, c.DurationMs, escapePipes(errs))
 	}
 
+	if fss := report.Summary.ResourceSnapshot.Filesystems; len(fss) > 0 {
+		md += "\n## Filesystems\n\n"
+		md += "| Path | Used | Size | Inodes used | Mount point | FS type | Same filesystem |\n|---|---:|---:|---:|---|---|---|\n"
+		for _, fs := range fss {
+			inodes := "n/a"
+			if fs.InodesTotal > 0 {
+				inodes = fmt.Sprintf("%.1f%% (%d of %d)", fs.InodesUsedPercent, fs.InodesUsed, fs.InodesTotal)
+			}
+			md += fmt.Sprintf("| `%s` | %.1f%% | %s | %s | %s | %s | %s |\n", fs.Path, fs.UsedPercent, humanBytes(fs.TotalBytes), inodes,
+				fallback(fs.MountPoint, "n/a"), fallback(fs.FSType, "n/a"), fallback(strings.Join(fs.SameDevicePaths, ", "), "-"))
+		}
+	}
+
+	if l := report.Summary.ResourceSnapshot.Limits; l != nil {
+		md += "\n## Container limits vs host\n\n"
+		md += "| Resource | Limits (running containers) | Host | Ratio | Containers without limit |\n|---|---:|---:|---:|---:|\n"
+		md += fmt.Sprintf("| Memory | %s | %s | %.0f%% | %d |\n", humanBytes(l.MemoryLimitBytes), humanBytes(l.HostMemoryBytes), l.MemoryOvercommitRatio*100, l.ContainersWithoutMemoryLimit)
+		md += fmt.Sprintf("| CPU | %.2f | %d | %.0f%% | %d |\n", l.CPULimitCores, l.HostCPUs, l.CPUOvercommitRatio*100, l.ContainersWithoutCPULimit)
+	}
+
+	if cp := report.CleanupPlan; cp != nil {
+		md += "\n## Cleanup plan\n\n"
+		md += fmt.Sprintf("Space each prune operation would free on its own (builder prune: unused for %s).\n\n", cp.BuilderPruneUntil)
+		md += "| Operation | Risk | Objects | Reclaimable | Command |\n|---|---|---:|---:|---|\n"
+		for _, op := range cp.Operations {
+			md += fmt.Sprintf("| %s | %s | %d | %s | `%s` |\n", escapePipes(op.Description), op.Risk, op.Objects, humanBytes(op.ReclaimableBytes), op.Command)
+		}
+	}
+
+	if inv := report.Inventory; inv != nil && len(inv.PublishedPorts) > 0 {
+		md += "\n## Published ports\n\n"
+		md += "| Container | Host address | Host port | Container port | Exposure | Service |\n|---|---|---:|---|---|---|\n"
+		for _, p := range inv.PublishedPorts {
+			exposure := "restricted"
+			if p.Public {
+				exposure = "**all interfaces**"
+			}
+			md += fmt.Sprintf("| `%s` | %s | %d | %d/%s | %s | %s |\n", p.ContainerName, fallback(p.HostIP, "*"), p.HostPort, p.ContainerPort, p.Protocol, exposure, fallback(p.Service, "-"))
+		}
+	}
+
+	if inv := report.Inventory; inv != nil && len(inv.LogPolicies) > 0 {
+		md += "\n## Log policy\n\n"
+		md += "| Container | Driver | Retention | Log size |\n|---|---|---|---:|\n"
+		for _, p := range inv.LogPolicies {
+			policy := p.Policy
+			if !p.Bounded {
+				policy = "**" + policy + "**"
+			}
+			size := "-"
+			if p.LogSizeBytes > 0 {
+				size = humanBytes(p.LogSizeBytes)
+			}
+			md += fmt.Sprintf("| `%s` | %s | %s | %s |\n", p.ContainerName, p.Driver, escapePipes(policy), size)
+		}
+	}
+
 	md += "\n## Findings\n\n"
 
 	md += "This report is **read-only**. It suggests actions but does not execute them.\n\n"
@@ -502,6 +648,18 @@ func generateMarkdownv1(report *v1.Report) (string, error) {
 			}
 			md += "\n"
 		}
+
+		if len(f.References) > 0 {
+			md += "**References**\n\n"
+			for _, r := range f.References {
+				if r.URL != "" {
+					md += fmt.Sprintf("- [%s](%s)\n", r.Label, r.URL)
+				} else {
+					md += fmt.Sprintf("- %s\n", r.Label)
+				}
+			}
+			md += "\n"
+		}
 	}
 
 	return md, nil
@@ -595,6 +753,7 @@ func generateHTML(report *types.Report) (string, error) {
             <th>Used (%)</th>
             <th>Used (Bytes)</th>
             <th>Total (Bytes)</th>
+            <th>Inodes Used (%)</th>
         </tr>
         {{range $path, $disk := .Host.DiskUsage}}
         <tr>
@@ -602,6 +761,7 @@ func generateHTML(report *types.Report) (string, error) {
             <td>{{printf "%.2f" $disk.UsedPercent}}</td>
             <td>{{$disk.Used}}</td>
             <td>{{$disk.Total}}</td>
+            <td>{{printf "%.2f" $disk.InodesUsedPercent}}</td>
         </tr>
         {{end}}
     </table>
@@ -684,11 +844,11 @@ func generateMarkdown(report *types.Report) (string, error) {
 - **Architecture:** %s
 
 ### Disk Usage
-| Path | Used (%%) | Used (Bytes) | Total (Bytes) |
-|------|-----------|--------------|---------------|
+| Path | Used (%%) | Used (Bytes) | Total (Bytes) | Inodes Used (%%) |
+|------|-----------|--------------|---------------|------------------|
 `, report.Timestamp.Format("2006-01-02 15:04:05"), report.Host.OS, report.Host.Arch)
 	for path, disk := range report.Host.DiskUsage {
-		md += fmt.Sprintf("| %s | %.2f | %d | %d |\n", path, disk.UsedPercent, disk.Used, disk.Total)
+		md += fmt.Sprintf("| %s | %.2f | %d | %d | %.2f |\n", path, disk.UsedPercent, disk.Used, disk.Total, disk.InodesUsedPercent)
 	}
 	md += fmt.Sprintf(`
 
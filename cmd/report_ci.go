@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+)
+
+// gitlabCodeQualityIssue is a single entry of a GitLab Code Quality report.
+// See https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool
+type gitlabCodeQualityIssue struct {
+	Description string                    `json:"description"`
+	CheckName   string                    `json:"check_name"`
+	Fingerprint string                    `json:"fingerprint"`
+	Severity    string                    `json:"severity"`
+	Location    gitlabCodeQualityLocation `json:"location"`
+}
+
+type gitlabCodeQualityLocation struct {
+	Path  string                 `json:"path"`
+	Lines gitlabCodeQualityLines `json:"lines"`
+}
+
+type gitlabCodeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// generateGitLabCodeQualityv1 renders the v1 findings as a GitLab Code Quality JSON artifact.
+func generateGitLabCodeQualityv1(report *v1.Report) (string, error) {
+	issues := make([]gitlabCodeQualityIssue, 0, len(report.Findings))
+	for _, f := range report.Findings {
+		desc := f.Title
+		if f.Summary != "" {
+			desc = fmt.Sprintf("%s: %s", f.ID, f.Summary)
+		}
+		sum := md5.Sum([]byte(report.Target.Host.Hostname + "|" + f.Fingerprint))
+		issues = append(issues, gitlabCodeQualityIssue{
+			Description: desc,
+			CheckName:   f.ID,
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Severity:    codeQualitySeverity(f.Severity, f.Confidence),
+			Location: gitlabCodeQualityLocation{
+				Path:  findingLocation(report, f),
+				Lines: gitlabCodeQualityLines{Begin: 1},
+			},
+		})
+	}
+	b, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
+// codeQualitySeverity maps v1 severity (and confidence) onto the GitLab scale:
+// blocker, critical, major, minor, info.
+func codeQualitySeverity(sev, confidence string) string {
+	switch strings.ToLower(strings.TrimSpace(sev)) {
+	case "critical":
+		if strings.ToLower(confidence) == "high" {
+			return "blocker"
+		}
+		return "critical"
+	case "warning":
+		if strings.ToLower(confidence) == "low" {
+			return "minor"
+		}
+		return "major"
+	default:
+		return "info"
+	}
+}
+
+// findingLocation returns a stable pseudo-path for a finding. Findings are about
+// a Docker host rather than a source file, so the path identifies the host and scope.
+func findingLocation(report *v1.Report, f v1.Finding) string {
+	if f.Scope.Path != "" {
+		return f.Scope.Path
+	}
+	loc := "docker://" + fallback(report.Target.Host.Hostname, "localhost")
+	if f.Scope.ContainerName != "" {
+		loc += "/" + strings.TrimPrefix(f.Scope.ContainerName, "/")
+	} else if f.Scope.ContainerID != "" {
+		loc += "/" + f.Scope.ContainerID
+	}
+	return loc
+}
+
+// generateGitHubAnnotationsv1 renders the v1 findings as GitHub Actions workflow commands
+// (::error, ::warning, ::notice), one per line.
+func generateGitHubAnnotationsv1(report *v1.Report) (string, error) {
+	var b strings.Builder
+	for _, f := range report.Findings {
+		level := "notice"
+		switch f.Severity {
+		case "critical":
+			level = "error"
+		case "warning":
+			level = "warning"
+		}
+		title := f.ID
+		if f.Title != "" {
+			title = fmt.Sprintf("%s: %s", f.ID, f.Title)
+		}
+		msg := f.Summary
+		if msg == "" {
+			msg = f.Title
+		}
+		msg = fmt.Sprintf("%s [%s]", msg, findingLocation(report, f))
+		fmt.Fprintf(&b, "::%s title=%s::%s\n", level, escapeGitHubProperty(title), escapeGitHubData(msg))
+	}
+	return b.String(), nil
+}
+
+// escapeGitHubData escapes a workflow command message.
+func escapeGitHubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeGitHubProperty escapes a workflow command property value.
+func escapeGitHubProperty(s string) string {
+	s = escapeGitHubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -25,6 +26,8 @@ func TestGenerateMarkdownv1_BasicSections(t *testing.T) {
 			Counts: v1.SummaryCounts{ContainersRunning: 1, ContainersStopped: 0, Images: 2, Volumes: 1},
 			ResourceSnapshot: v1.SummaryResourceSnapshot{
 				DockerSystemDf: v1.DockerSystemDf{ImagesTotalBytes: 1024, BuildCacheTotalBytes: 2048},
+				Filesystems:    []v1.Filesystem{{Path: "/var/lib/docker", UsedPercent: 40, TotalBytes: 1 << 30, InodesUsed: 90, InodesTotal: 100, InodesUsedPercent: 90, MountPoint: "/", FSType: "ext4", SameDevicePaths: []string{"/srv/data"}}},
+				Limits:         &v1.LimitsSummary{MemoryLimitBytes: 3 << 30, HostMemoryBytes: 2 << 30, MemoryOvercommitRatio: 1.5, CPULimitCores: 1, HostCPUs: 4, CPUOvercommitRatio: 0.25},
 			},
 			FindingCounts: v1.SummaryFindingCounts{Critical: 1, Warning: 0, Info: 0},
 		},
@@ -38,8 +41,20 @@ func TestGenerateMarkdownv1_BasicSections(t *testing.T) {
 				Category:    "storage",
 				Title:       "Docker storage usage is high",
 				Summary:     "Example summary",
+				References:  []v1.Reference{{Kind: "cis", Label: "CIS Docker Benchmark v1.2.0 5.4", URL: "https://www.cisecurity.org/benchmark/docker"}},
 			},
 		},
+		Inventory: &v1.Inventory{
+			PublishedPorts: []v1.PublishedPort{
+				{ContainerID: "abc", ContainerName: "cache", HostIP: "0.0.0.0", HostPort: 6379, ContainerPort: 6379, Protocol: "tcp", Public: true, Service: "Redis"},
+			},
+			LogPolicies: []v1.LogPolicy{
+				{ContainerID: "abc", ContainerName: "cache", Driver: "json-file", Policy: "unbounded", LogSizeBytes: 2048},
+			},
+		},
+		CleanupPlan: &v1.CleanupPlan{BuilderPruneUntil: "168h0m0s", Operations: []v1.CleanupOperation{
+			{Name: "builder-prune", Command: "docker builder prune -f --filter until=168h0m0s", Risk: "safe", Description: "Remove build cache records not in use", Objects: 3, ReclaimableBytes: 2048},
+		}},
 	}
 
 	out, err := generateMarkdownv1(r)
@@ -51,6 +66,15 @@ func TestGenerateMarkdownv1_BasicSections(t *testing.T) {
 		"## Summary",
 		"## Findings",
 		"DOCKER_STORAGE_BLOAT",
+		"| `/var/lib/docker` | 40.0% | 1.00 GB | 90.0% (90 of 100) | / | ext4 | /srv/data |",
+		"## Container limits vs host",
+		"| Memory | 3.00 GB | 2.00 GB | 150% | 0 |",
+		"## Cleanup plan",
+		"`docker builder prune -f --filter until=168h0m0s`",
+		"- [CIS Docker Benchmark v1.2.0 5.4](https://www.cisecurity.org/benchmark/docker)",
+		"| `cache` | 0.0.0.0 | 6379 | 6379/tcp | **all interfaces** | Redis |",
+		"## Log policy",
+		"| `cache` | json-file | **unbounded** | 2 KB |",
 	} {
 		if !strings.Contains(out, needle) {
 			t.Fatalf("markdown missing %q\n\n%s", needle, out)
@@ -58,3 +82,60 @@ func TestGenerateMarkdownv1_BasicSections(t *testing.T) {
 	}
 }
 
+
+func TestGenerateCIFormatsv1(t *testing.T) {
+	r := &v1.Report{
+		SchemaVersion: "1.0",
+		Target:        v1.Target{Host: v1.TargetHost{Hostname: "ci-host"}},
+		Findings: []v1.Finding{
+			{
+				ID:          "OOM_KILLED",
+				Fingerprint: "OOM_KILLED:container=abc123",
+				Severity:    "critical",
+				Confidence:  "high",
+				Title:       "Container was killed by OOM",
+				Summary:     "Container /app (abc123) was killed, 100%\nout of memory",
+				Scope:       v1.Scope{ContainerID: "abc123", ContainerName: "/app"},
+			},
+			{
+				ID:          "VOLUME_BLOAT",
+				Fingerprint: "VOLUME_BLOAT:volumes_unused",
+				Severity:    "warning",
+				Confidence:  "medium",
+				Title:       "Unused Docker volumes detected",
+			},
+		},
+	}
+
+	cq, err := generateGitLabCodeQualityv1(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var issues []gitlabCodeQualityIssue
+	if err := json.Unmarshal([]byte(cq), &issues); err != nil {
+		t.Fatalf("invalid code quality JSON: %v\n%s", err, cq)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 code quality issues, got %d", len(issues))
+	}
+	if issues[0].Severity != "blocker" || issues[1].Severity != "major" {
+		t.Fatalf("unexpected severities: %q, %q", issues[0].Severity, issues[1].Severity)
+	}
+	if issues[0].Location.Path != "docker://ci-host/app" || len(issues[0].Fingerprint) != 32 {
+		t.Fatalf("unexpected location/fingerprint: %+v", issues[0])
+	}
+
+	gh, err := generateGitHubAnnotationsv1(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, needle := range []string{
+		"::error title=OOM_KILLED%3A Container was killed by OOM::",
+		"100%25%0Aout of memory",
+		"::warning title=VOLUME_BLOAT%3A Unused Docker volumes detected::",
+	} {
+		if !strings.Contains(gh, needle) {
+			t.Fatalf("annotations missing %q\n\n%s", needle, gh)
+		}
+	}
+}
@@ -13,6 +13,9 @@ import (
 
 	"github.com/dashu-baba/docker-doctor/internal/collector"
 	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/lifecycle"
+	"github.com/dashu-baba/docker-doctor/internal/metrics"
+	"github.com/dashu-baba/docker-doctor/internal/notify"
 	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
 	"github.com/dashu-baba/docker-doctor/internal/types"
 	"github.com/spf13/cobra"
@@ -30,7 +33,10 @@ containers, images, volumes, and disk usage. Writes scan.json + human reports.`,
 		apiVersion, _ := cmd.Flags().GetString("api-version")
 		exitCode, _ := cmd.Flags().GetBool("exit-code")
 		verbose, _ := cmd.Flags().GetBool("verbose")
-		return runScan(outputDir, formats, apiVersion, exitCode, verbose)
+		promFile, _ := cmd.Flags().GetString("prom-file")
+		sample, _ := cmd.Flags().GetDuration("sample")
+		lifecycleFile, _ := cmd.Flags().GetString("lifecycle-file")
+		return runScan(outputDir, formats, apiVersion, exitCode, verbose, promFile, sample, lifecycleFile)
 	},
 }
 
@@ -46,32 +52,36 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	scanCmd.Flags().StringP("output-dir", "o", "./out", "Output directory. Artifacts are written to <output-dir>/<scanId>/")
-	scanCmd.Flags().String("formats", "json,html,md", "Comma-separated output formats: json,html,md")
+	scanCmd.Flags().String("formats", "json,html,md", "Comma-separated output formats: json,html,md,gitlab,github,prom")
 	scanCmd.Flags().String("api-version", "", "Docker API version to use (overrides config)")
+	scanCmd.Flags().String("lifecycle-file", "", "Docker Engine lifecycle table (JSON) replacing the embedded one (overrides config)")
 	scanCmd.Flags().Bool("exit-code", false, "If set, exit non-zero when findings are WARN/CRITICAL (CI mode)")
 	scanCmd.Flags().Bool("verbose", false, "Enable debug logging to stderr")
+	scanCmd.Flags().String("prom-file", "", "Path of the .prom file written by the prom format (default <output-dir>/docker_doctor.prom)")
+	scanCmd.Flags().Duration("sample", 0, "Stream container stats for this long (e.g. 30s) to detect CPU throttling and memory/PID pressure (overrides config)")
 }
 
-func runScan(outputDir string, formats string, apiVersion string, exitCode bool, verbose bool) error {
-	startedAt := time.Now()
-
-	cfg, err := config.Load(configFile)
+func runScan(outputDir string, formats string, apiVersion string, exitCode bool, verbose bool, promFile string, sample time.Duration, lifecycleFile string) error {
+	cfg, err := loadScanConfig()
 	if err != nil {
 		return ExitError{Code: 3, Err: err}
 	}
 
-	// Check full mode availability
-	if cfg.Scan.Mode == "full" && runtime.GOOS != "linux" {
-		fmt.Fprintf(os.Stderr, "Warning: Full scan mode is not supported on %s. Host filesystem access is required for full scans. Falling back to basic mode.\n", runtime.GOOS)
-		cfg.Scan.Mode = "basic"
-	}
-
 	// Use config values, override with flags if provided
 	if apiVersion == "" {
 		apiVersion = cfg.Scan.Version
 	}
+	if sample < 0 {
+		return ExitError{Code: 3, Err: fmt.Errorf("--sample must be non-negative, got %s", sample)}
+	}
+	if sample > 0 {
+		cfg.Scan.Sample = sample
+	}
+	if err := applyLifecycleFile(cfg, lifecycleFile); err != nil {
+		return ExitError{Code: 3, Err: err}
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Scan.Timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), scanTimeout(cfg))
 	defer cancel()
 
 	// Optional debug logging (kept off by default for clean CLI UX).
@@ -81,18 +91,14 @@ func runScan(outputDir string, formats string, apiVersion string, exitCode bool,
 		ctx = collector.WithLogger(ctx, l)
 	}
 
-	report, err := collector.Collect(ctx, apiVersion, cfg)
+	report, v1Report, err := performScan(ctx, cfg, apiVersion)
 	if err != nil {
-		return ExitError{Code: 3, Err: fmt.Errorf("failed to collect data: %w", err)}
+		return ExitError{Code: 3, Err: err}
 	}
 
-	finishedAt := time.Now()
-
-	v1Report := v1.BuildFromV0(ctx, report, cfg, apiVersion, startedAt, finishedAt, toolVersion, toolGitCommit, toolBuildTime)
-
 	selected := parseFormats(formats)
 	if len(selected) == 0 {
-		return ExitError{Code: 3, Err: fmt.Errorf("no formats selected (use --formats json,html,md)")}
+		return ExitError{Code: 3, Err: fmt.Errorf("no formats selected (use --formats json,html,md,gitlab,github,prom)")}
 	}
 
 	runDir := filepath.Join(outputDir, v1Report.Scan.ScanID)
@@ -139,8 +145,52 @@ func runScan(outputDir string, formats string, apiVersion string, exitCode bool,
 		written = append(written, mdPath)
 	}
 
+	if selected["gitlab"] {
+		cq, err := generateGitLabCodeQualityv1(&v1Report)
+		if err != nil {
+			return ExitError{Code: 3, Err: fmt.Errorf("failed to generate GitLab Code Quality report: %w", err)}
+		}
+		cqPath := filepath.Join(runDir, "gl-code-quality-report.json")
+		if err := os.WriteFile(cqPath, []byte(cq), 0o644); err != nil {
+			return ExitError{Code: 3, Err: fmt.Errorf("failed to write gl-code-quality-report.json: %w", err)}
+		}
+		written = append(written, cqPath)
+	}
+
+	if selected["github"] {
+		// Workflow commands are picked up by the Actions runner from stdout.
+		annotations, err := generateGitHubAnnotationsv1(&v1Report)
+		if err != nil {
+			return ExitError{Code: 3, Err: fmt.Errorf("failed to generate GitHub annotations: %w", err)}
+		}
+		fmt.Print(annotations)
+	}
+
+	if selected["prom"] {
+		// Stable path (not per-scan) so node_exporter's textfile collector always reads the latest scan.
+		if promFile == "" {
+			promFile = filepath.Join(outputDir, "docker_doctor.prom")
+		}
+		if err := metrics.WriteFile(promFile, metrics.FromScan(report, &v1Report)); err != nil {
+			return ExitError{Code: 3, Err: fmt.Errorf("failed to write %s: %w", promFile, err)}
+		}
+		written = append(written, promFile)
+	}
+
 	if len(written) > 0 {
-		fmt.Printf("Wrote %d artifact(s) to %s\n", len(written), runDir)
+		// The .prom file lives outside runDir, so list every path rather than one directory.
+		fmt.Printf("Wrote %d artifact(s):\n", len(written))
+		for _, path := range written {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+
+	if len(cfg.Notify.Webhooks) > 0 {
+		previous, err := notify.LoadPrevious(outputDir, &v1Report)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load previous scan: %v\n", err)
+		}
+		notifyWebhooks(cfg, &v1Report, previous)
 	}
 
 	if exitCode {
@@ -155,6 +205,61 @@ func runScan(outputDir string, formats string, apiVersion string, exitCode bool,
 	return nil
 }
 
+// loadScanConfig loads the config file and downgrades full mode on platforms
+// where host filesystem access is unavailable.
+func loadScanConfig() (*config.Config, error) {
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check full mode availability
+	if cfg.Scan.Mode == "full" && runtime.GOOS != "linux" {
+		fmt.Fprintf(os.Stderr, "Warning: Full scan mode is not supported on %s. Host filesystem access is required for full scans. Falling back to basic mode.\n", runtime.GOOS)
+		cfg.Scan.Mode = "basic"
+	}
+	return cfg, nil
+}
+
+// applyLifecycleFile applies the --lifecycle-file override and loads the resulting table
+// once, so a bad file fails the command up front instead of every rule evaluation.
+func applyLifecycleFile(cfg *config.Config, lifecycleFile string) error {
+	if lifecycleFile != "" {
+		cfg.Rules.EngineVersion.LifecycleFile = lifecycleFile
+	}
+	_, err := lifecycle.Load(cfg.Rules.EngineVersion.LifecycleFile)
+	return err
+}
+
+// scanTimeout is the deadline of one scan: the configured timeout plus the stats sampling window.
+func scanTimeout(cfg *config.Config) time.Duration {
+	return time.Duration(cfg.Scan.Timeout)*time.Second + cfg.Scan.Sample
+}
+
+// performScan runs the collectors and rules once and returns both the legacy
+// report and the v1 contract built from it.
+func performScan(ctx context.Context, cfg *config.Config, apiVersion string) (*types.Report, v1.Report, error) {
+	startedAt := time.Now()
+
+	report, err := collector.Collect(ctx, apiVersion, cfg)
+	if err != nil {
+		return nil, v1.Report{}, fmt.Errorf("failed to collect data: %w", err)
+	}
+
+	finishedAt := time.Now()
+
+	v1Report := v1.BuildFromV0(ctx, report, cfg, apiVersion, startedAt, finishedAt, toolVersion, toolGitCommit, toolBuildTime)
+	return report, v1Report, nil
+}
+
+// notifyWebhooks delivers the scan to the configured webhooks. Delivery failures are
+// reported on stderr but never change the scan exit code.
+func notifyWebhooks(cfg *config.Config, current, previous *v1.Report) {
+	for _, err := range notify.New().Send(context.Background(), cfg.Notify.Webhooks, current, previous) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
 func parseFormats(s string) map[string]bool {
 	out := map[string]bool{}
 	for _, p := range strings.Split(s, ",") {
@@ -163,7 +268,7 @@ func parseFormats(s string) map[string]bool {
 			continue
 		}
 		switch k {
-		case "json", "html", "md":
+		case "json", "html", "md", "gitlab", "github", "prom":
 			out[k] = true
 		}
 	}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dashu-baba/docker-doctor/internal/collector"
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/metrics"
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Scan periodically and expose Prometheus metrics over HTTP",
+	Long: `Run docker-doctor as a long-lived process (e.g. a sidecar). The host is scanned
+every --interval and the latest result is exposed on:
+
+  /metrics      Prometheus metrics
+  /healthz      200 when the latest scan is recent and succeeded, 503 otherwise
+  /scan.json    v1 scan contract of the latest successful scan
+  /report.html  HTML report of the latest successful scan`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		apiVersion, _ := cmd.Flags().GetString("api-version")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		lifecycleFile, _ := cmd.Flags().GetString("lifecycle-file")
+		return runServe(listen, interval, apiVersion, verbose, lifecycleFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("listen", ":9323", "Address to listen on for HTTP requests")
+	serveCmd.Flags().Duration("interval", 5*time.Minute, "Interval between scans")
+	serveCmd.Flags().String("api-version", "", "Docker API version to use (overrides config)")
+	serveCmd.Flags().String("lifecycle-file", "", "Docker Engine lifecycle table (JSON) replacing the embedded one (overrides config)")
+	serveCmd.Flags().Bool("verbose", false, "Enable debug logging to stderr")
+}
+
+// serveState holds the latest scan result shared between the scan loop and HTTP handlers.
+type serveState struct {
+	mu          sync.RWMutex
+	interval    time.Duration
+	v0          *types.Report
+	v1          *v1.Report
+	scanJSON    []byte
+	reportHTML  []byte
+	lastErr     error
+	lastAttempt time.Time
+	lastSuccess time.Time
+	successes   uint64
+	failures    uint64
+}
+
+func (s *serveState) recordSuccess(v0 *types.Report, r v1.Report, at time.Time) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	html, err := generateHTMLv1(&r)
+	if err != nil {
+		return fmt.Errorf("failed to generate HTML report: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.v0 = v0
+	s.v1 = &r
+	s.scanJSON = data
+	s.reportHTML = []byte(html)
+	s.lastErr = nil
+	s.lastAttempt = at
+	s.lastSuccess = at
+	s.successes++
+	return nil
+}
+
+func (s *serveState) recordFailure(err error, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+	s.lastAttempt = at
+	s.failures++
+}
+
+func runServe(listen string, interval time.Duration, apiVersion string, verbose bool, lifecycleFile string) error {
+	if interval <= 0 {
+		return ExitError{Code: 3, Err: fmt.Errorf("--interval must be greater than 0")}
+	}
+
+	cfg, err := loadScanConfig()
+	if err != nil {
+		return ExitError{Code: 3, Err: err}
+	}
+	if apiVersion == "" {
+		apiVersion = cfg.Scan.Version
+	}
+	if err := applyLifecycleFile(cfg, lifecycleFile); err != nil {
+		return ExitError{Code: 3, Err: err}
+	}
+
+	// Bind before the first scan so a busy port fails right away, not after a full scan.
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return ExitError{Code: 3, Err: fmt.Errorf("failed to listen on %s: %w", listen, err)}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := log.New(os.Stderr, "docker-doctor ", log.LstdFlags)
+	state := &serveState{interval: interval}
+
+	srv := &http.Server{
+		Addr:              listen,
+		Handler:           newServeMux(state),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	srvErr := make(chan error, 1)
+	go func() {
+		logger.Printf("serve: listening on %s (interval %s)", listen, interval)
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			srvErr <- err
+		}
+		close(srvErr)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		serveScanOnce(ctx, cfg, apiVersion, state, logger, verbose)
+
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return srv.Shutdown(shutdownCtx)
+		case err, ok := <-srvErr:
+			if ok && err != nil {
+				return ExitError{Code: 3, Err: fmt.Errorf("http server failed: %w", err)}
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func serveScanOnce(ctx context.Context, cfg *config.Config, apiVersion string, state *serveState, logger *log.Logger, verbose bool) {
+	scanCtx, cancel := context.WithTimeout(ctx, scanTimeout(cfg))
+	defer cancel()
+	if verbose {
+		scanCtx = collector.WithLogger(scanCtx, logger)
+	}
+
+	state.mu.RLock()
+	previous := state.v1
+	state.mu.RUnlock()
+
+	report, v1Report, err := performScan(scanCtx, cfg, apiVersion)
+	now := time.Now()
+	if err == nil {
+		err = state.recordSuccess(report, v1Report, now)
+	}
+	if err != nil {
+		state.recordFailure(err, now)
+		logger.Printf("serve: scan failed: %v", err)
+		return
+	}
+	logger.Printf("serve: scan %s ok (%d finding(s))", v1Report.Scan.ScanID, len(v1Report.Findings))
+
+	if len(cfg.Notify.Webhooks) > 0 {
+		notifyWebhooks(cfg, &v1Report, previous)
+	}
+}
+
+func newServeMux(state *serveState) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		state.mu.RLock()
+		fams := metrics.FromScan(state.v0, state.v1)
+
+		last := metrics.NewGauge("docker_doctor_last_scan_success", "Whether the most recent scan attempt succeeded (1 = ok).")
+		if !state.lastAttempt.IsZero() {
+			v := 1.0
+			if state.lastErr != nil {
+				v = 0
+			}
+			last.Add(v)
+		}
+		scans := metrics.NewCounter("docker_doctor_scans_total", "Scans attempted since start by result.")
+		scans.Add(float64(state.successes), "result", "success")
+		scans.Add(float64(state.failures), "result", "error")
+		state.mu.RUnlock()
+
+		fams = append(fams, last, scans)
+		var buf bytes.Buffer
+		if err := metrics.Encode(&buf, fams); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	})
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		state.mu.RLock()
+		defer state.mu.RUnlock()
+		switch {
+		case state.lastSuccess.IsZero() && state.lastErr == nil:
+			http.Error(w, "no scan completed yet", http.StatusServiceUnavailable)
+		case state.lastErr != nil:
+			http.Error(w, "last scan failed: "+state.lastErr.Error(), http.StatusServiceUnavailable)
+		case time.Since(state.lastSuccess) > 2*state.interval+time.Minute:
+			http.Error(w, "last successful scan is stale", http.StatusServiceUnavailable)
+		default:
+			fmt.Fprintln(w, "ok")
+		}
+	})
+
+	mux.HandleFunc("/scan.json", func(w http.ResponseWriter, r *http.Request) {
+		state.mu.RLock()
+		data := state.scanJSON
+		state.mu.RUnlock()
+		if data == nil {
+			http.Error(w, "no scan completed yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	})
+
+	mux.HandleFunc("/report.html", func(w http.ResponseWriter, r *http.Request) {
+		state.mu.RLock()
+		data := state.reportHTML
+		state.mu.RUnlock()
+		if data == nil {
+			http.Error(w, "no scan completed yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(data)
+	})
+
+	return mux
+}
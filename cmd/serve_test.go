@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func TestServeMux_Endpoints(t *testing.T) {
+	state := &serveState{interval: time.Minute}
+	mux := newServeMux(state)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if rec := get("/healthz"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before first scan, got %d", rec.Code)
+	}
+	if rec := get("/scan.json"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 for scan.json before first scan, got %d", rec.Code)
+	}
+
+	r := v1.Report{
+		SchemaVersion: "1.0",
+		Scan:          v1.Scan{ScanID: "serve-test", FinishedAt: time.Now()},
+		Findings:      []v1.Finding{{ID: "OOM_KILLED", Severity: "critical"}},
+	}
+	if err := state.recordSuccess(&types.Report{}, r, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := get("/healthz"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 after scan, got %d", rec.Code)
+	}
+	if rec := get("/scan.json"); !strings.Contains(rec.Body.String(), `"scanId": "serve-test"`) {
+		t.Fatalf("unexpected scan.json body: %s", rec.Body.String())
+	}
+	if rec := get("/report.html"); !strings.Contains(rec.Body.String(), "Docker Host Doctor Report") {
+		t.Fatalf("unexpected report.html body")
+	}
+	body := get("/metrics").Body.String()
+	for _, needle := range []string{
+		`docker_doctor_findings{rule="OOM_KILLED",severity="critical"} 1`,
+		"docker_doctor_last_scan_success 1",
+		`docker_doctor_scans_total{result="success"} 1`,
+	} {
+		if !strings.Contains(body, needle) {
+			t.Fatalf("metrics missing %q\n\n%s", needle, body)
+		}
+	}
+
+	state.recordFailure(errors.New("docker unreachable"), time.Now())
+	if rec := get("/healthz"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after failed scan, got %d", rec.Code)
+	}
+}
+
+func TestRunServe_FailsFastOnStartupErrors(t *testing.T) {
+	cfg := &config.Config{}
+	if err := applyLifecycleFile(cfg, filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatalf("expected a missing lifecycle file to be rejected")
+	}
+	if err := applyLifecycleFile(cfg, ""); err == nil {
+		t.Fatalf("expected the configured file to stay in effect and be rejected")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	prev := configFile
+	configFile = filepath.Join("..", "doctor.yml")
+	defer func() { configFile = prev }()
+	done := make(chan error, 1)
+	go func() { done <- runServe(ln.Addr().String(), time.Hour, "", false, "") }()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "failed to listen") {
+			t.Fatalf("expected a listen error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("runServe did not fail on a busy port")
+	}
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/spf13/cobra"
+
+	"github.com/dashu-baba/docker-doctor/internal/collector"
+	"github.com/dashu-baba/docker-doctor/internal/metrics"
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+	"github.com/dashu-baba/docker-doctor/internal/watch"
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch Docker events and emit per-container findings as they appear",
+	Long: `Subscribe to the Docker events stream (die, start, oom, restart, health_status), keep
+per-container counters over a sliding window and re-evaluate the affected container's
+rules on every event. Findings are emitted when they first appear or change severity.
+
+Outputs (--output, comma-separated):
+  ndjson   one JSON record per finding on stdout (default)
+  webhook  deliver findings to the webhooks configured under notify.webhooks
+  metrics  expose event/finding counters on --listen at /metrics`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		window, _ := cmd.Flags().GetDuration("window")
+		outputs, _ := cmd.Flags().GetString("output")
+		listen, _ := cmd.Flags().GetString("listen")
+		apiVersion, _ := cmd.Flags().GetString("api-version")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		return runWatch(window, outputs, listen, apiVersion, verbose)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().Duration("window", 10*time.Minute, "Sliding window for per-container event counters")
+	watchCmd.Flags().String("output", "ndjson", "Comma-separated outputs: ndjson,webhook,metrics")
+	watchCmd.Flags().String("listen", ":9323", "Address for the metrics output")
+	watchCmd.Flags().String("api-version", "", "Docker API version to use (overrides config)")
+	watchCmd.Flags().Bool("verbose", false, "Enable debug logging to stderr")
+}
+
+// watchRecord is one NDJSON line emitted by watch mode.
+type watchRecord struct {
+	ObservedAt  time.Time  `json:"observedAt"`
+	Event       string     `json:"event"`
+	ContainerID string     `json:"containerId"`
+	Finding     v1.Finding `json:"finding"`
+}
+
+// watchStats holds counters exposed by the metrics output.
+type watchStats struct {
+	mu        sync.Mutex
+	connected bool
+	events    map[string]int
+	findings  map[[2]string]int
+}
+
+func runWatch(window time.Duration, outputs string, listen string, apiVersion string, verbose bool) error {
+	if window <= 0 {
+		return ExitError{Code: 3, Err: fmt.Errorf("--window must be greater than 0")}
+	}
+	selected := map[string]bool{}
+	for _, o := range strings.Split(outputs, ",") {
+		switch o = strings.ToLower(strings.TrimSpace(o)); o {
+		case "":
+		case "ndjson", "webhook", "metrics":
+			selected[o] = true
+		default:
+			return ExitError{Code: 3, Err: fmt.Errorf("unsupported output %q (use ndjson,webhook,metrics)", o)}
+		}
+	}
+	if len(selected) == 0 {
+		return ExitError{Code: 3, Err: fmt.Errorf("no outputs selected (use --output ndjson,webhook,metrics)")}
+	}
+
+	cfg, err := loadScanConfig()
+	if err != nil {
+		return ExitError{Code: 3, Err: err}
+	}
+	if apiVersion == "" {
+		apiVersion = cfg.Scan.Version
+	}
+	if selected["webhook"] && len(cfg.Notify.Webhooks) == 0 {
+		return ExitError{Code: 3, Err: fmt.Errorf("webhook output selected but no notify.webhooks configured")}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := log.New(os.Stderr, "docker-doctor ", log.LstdFlags)
+	tracker := watch.NewTracker(window, cfg)
+	stats := &watchStats{events: map[string]int{}, findings: map[[2]string]int{}}
+
+	if selected["metrics"] {
+		srv := &http.Server{Addr: listen, Handler: newWatchMux(tracker, stats), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Printf("watch: metrics server failed: %v", err)
+				stop()
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = srv.Shutdown(shutdownCtx)
+		}()
+	}
+
+	hostname, _ := os.Hostname()
+	enc := json.NewEncoder(os.Stdout)
+	emit := func(ev watch.Event, issues []types.Issue) {
+		findings := make([]v1.Finding, 0, len(issues))
+		stats.mu.Lock()
+		for _, is := range issues {
+			f := v1.FindingFromIssue(is)
+			findings = append(findings, f)
+			stats.findings[[2]string{f.ID, f.Severity}]++
+		}
+		stats.mu.Unlock()
+
+		if selected["ndjson"] {
+			for _, f := range findings {
+				_ = enc.Encode(watchRecord{ObservedAt: ev.Time.UTC(), Event: ev.Kind, ContainerID: shortContainerID(ev.ContainerID), Finding: f})
+			}
+		}
+		if selected["webhook"] {
+			notifyWebhooks(cfg, watchReport(hostname, ev.Time, findings), nil)
+		}
+	}
+
+	var since time.Time
+	backoff := time.Second
+	for {
+		msgs, errs, err := collector.ContainerEvents(ctx, cfg.Scan.DockerHost, apiVersion, since)
+		if err != nil {
+			return ExitError{Code: 3, Err: fmt.Errorf("failed to subscribe to Docker events: %w", err)}
+		}
+		stats.setConnected(true)
+		if verbose {
+			logger.Printf("watch: subscribed to Docker events (window %s)", window)
+		}
+
+		streamErr := consumeEvents(ctx, msgs, errs, func(m events.Message) {
+			kind, detail, ok := watch.KindFromAction(m.Action)
+			if !ok {
+				return
+			}
+			at := time.Unix(0, m.TimeNano)
+			if m.TimeNano == 0 {
+				at = time.Unix(m.Time, 0)
+			}
+			// After a reconnect the daemon replays from `since`; skip events already handled.
+			if !since.IsZero() && !at.After(since) {
+				return
+			}
+			since = at
+			backoff = time.Second
+			ev := watch.Event{ContainerID: m.Actor.ID, Kind: kind, Detail: detail, Time: at}
+			if name := m.Actor.Attributes["name"]; name != "" {
+				ev.Name = "/" + strings.TrimPrefix(name, "/")
+			}
+
+			stats.mu.Lock()
+			stats.events[kind]++
+			stats.mu.Unlock()
+
+			// Re-inspect only the affected container; it may already be gone (e.g. --rm).
+			inspectCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Scan.Timeout)*time.Second)
+			info, err := collector.InspectContainer(inspectCtx, cfg.Scan.DockerHost, apiVersion, m.Actor.ID, cfg.Rules.SecretEnv.HashKeyEnv)
+			cancel()
+			if err != nil {
+				if verbose {
+					logger.Printf("watch: inspect %s failed: %v", shortContainerID(m.Actor.ID), err)
+				}
+				info = nil
+			}
+
+			if issues := tracker.Observe(ev, info); len(issues) > 0 {
+				emit(ev, issues)
+			}
+		})
+		stats.setConnected(false)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		logger.Printf("watch: event stream interrupted: %v (reconnecting in %s)", streamErr, backoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// consumeEvents handles messages until the stream fails or ctx is cancelled.
+func consumeEvents(ctx context.Context, msgs <-chan events.Message, errs <-chan error, handle func(events.Message)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			return err
+		case m := <-msgs:
+			handle(m)
+		}
+	}
+}
+
+func (s *watchStats) setConnected(v bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connected = v
+}
+
+// watchReport wraps findings emitted by watch mode in a minimal v1 report so the
+// webhook payloads match those of a scan.
+func watchReport(hostname string, at time.Time, findings []v1.Finding) *v1.Report {
+	counts := v1.SummaryFindingCounts{}
+	for _, f := range findings {
+		switch f.Severity {
+		case "critical":
+			counts.Critical++
+		case "warning":
+			counts.Warning++
+		default:
+			counts.Info++
+		}
+	}
+	return &v1.Report{
+		SchemaVersion: "1.0",
+		Tool:          v1.Tool{Name: "docker-host-doctor", Version: toolVersion, GitCommit: toolGitCommit, BuildTime: toolBuildTime},
+		Scan:          v1.Scan{ScanID: "watch-" + at.UTC().Format("20060102T150405Z"), StartedAt: at.UTC(), FinishedAt: at.UTC(), Mode: "watch", EffectiveMode: "watch"},
+		Target:        v1.Target{Host: v1.TargetHost{Hostname: hostname}},
+		Summary:       v1.Summary{FindingCounts: counts},
+		Findings:      findings,
+		Errors:        []string{},
+	}
+}
+
+func newWatchMux(tracker *watch.Tracker, stats *watchStats) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		evs := metrics.NewCounter("docker_doctor_watch_events_total", "Docker container events observed by kind.")
+		found := metrics.NewCounter("docker_doctor_watch_findings_total", "Findings emitted by watch mode by rule and severity.")
+		up := metrics.NewGauge("docker_doctor_watch_connected", "Whether the Docker event stream is connected (1 = connected).")
+		stats.mu.Lock()
+		for kind, n := range stats.events {
+			evs.Add(float64(n), "event", kind)
+		}
+		for k, n := range stats.findings {
+			found.Add(float64(n), "rule", k[0], "severity", k[1])
+		}
+		if stats.connected {
+			up.Add(1)
+		} else {
+			up.Add(0)
+		}
+		stats.mu.Unlock()
+
+		inWindow := metrics.NewGauge("docker_doctor_watch_container_events", "Container events inside the sliding window by kind.")
+		for id, byKind := range tracker.Counts(time.Now()) {
+			name := strings.TrimPrefix(tracker.Name(id), "/")
+			for kind, n := range byKind {
+				inWindow.Add(float64(n), "id", id, "name", name, "event", kind)
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := metrics.Encode(&buf, []*metrics.Family{up, evs, found, inWindow}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		stats.mu.Lock()
+		connected := stats.connected
+		stats.mu.Unlock()
+		if !connected {
+			http.Error(w, "event stream disconnected", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+func shortContainerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// collectHostCgroup inspects the cgroup hierarchy under root (normally "/"). cgroup v2 is
+// detected by /sys/fs/cgroup/cgroup.controllers (hybrid hosts mount v2 elsewhere and count
+// as v1). It returns nil when /sys/fs/cgroup is not mounted.
+func collectHostCgroup(root string) *types.HostCgroup {
+	base := filepath.Join(root, "sys", "fs", "cgroup")
+	if st, err := os.Stat(base); err != nil || !st.IsDir() {
+		return nil
+	}
+	cg := &types.HostCgroup{Version: "1", Systemd: hostUsesSystemd(root)}
+	if data, err := os.ReadFile(filepath.Join(base, "cgroup.controllers")); err == nil {
+		cg.Version = "2"
+		cg.Controllers = strings.Fields(string(data))
+	} else if f, err := os.Open(filepath.Join(root, "proc", "cgroups")); err == nil {
+		cg.Controllers = parseProcCgroups(f)
+		f.Close()
+	}
+	sort.Strings(cg.Controllers)
+	return cg
+}
+
+// parseProcCgroups returns the enabled controllers attached to a hierarchy from /proc/cgroups
+// ("#subsys_name hierarchy num_cgroups enabled").
+func parseProcCgroups(r io.Reader) []string {
+	var out []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 4 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if fields[1] != "0" && fields[3] == "1" {
+			out = append(out, fields[0])
+		}
+	}
+	return out
+}
+
+// hostUsesSystemd mirrors sd_booted(): /run/systemd/system exists, or PID 1 is systemd
+// (visible when the scanner shares the host PID namespace).
+func hostUsesSystemd(root string) bool {
+	if st, err := os.Stat(filepath.Join(root, "run", "systemd", "system")); err == nil && st.IsDir() {
+		return true
+	}
+	comm, err := os.ReadFile(filepath.Join(root, "proc", "1", "comm"))
+	return err == nil && strings.TrimSpace(string(comm)) == "systemd"
+}
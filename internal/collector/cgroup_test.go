@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testProcCgroups = `#subsys_name	hierarchy	num_cgroups	enabled
+cpuset	3	4	1
+cpu	5	60	1
+memory	0	80	0
+pids	7	60	1
+net_cls	0	1	1
+`
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseProcCgroups(t *testing.T) {
+	got := parseProcCgroups(strings.NewReader(testProcCgroups))
+	want := []string{"cpuset", "cpu", "pids"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestCollectHostCgroup(t *testing.T) {
+	v2 := t.TempDir()
+	writeTestFile(t, filepath.Join(v2, "sys/fs/cgroup/cgroup.controllers"), "cpuset cpu io memory pids\n")
+	if err := os.MkdirAll(filepath.Join(v2, "run/systemd/system"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	got := collectHostCgroup(v2)
+	if got == nil || got.Version != "2" || !got.Systemd || !reflect.DeepEqual(got.Controllers, []string{"cpu", "cpuset", "io", "memory", "pids"}) {
+		t.Fatalf("unexpected v2 result %+v", got)
+	}
+
+	v1 := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(v1, "sys/fs/cgroup/memory"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(v1, "proc/cgroups"), testProcCgroups)
+	writeTestFile(t, filepath.Join(v1, "proc/1/comm"), "init\n")
+	got = collectHostCgroup(v1)
+	if got == nil || got.Version != "1" || got.Systemd || !reflect.DeepEqual(got.Controllers, []string{"cpu", "cpuset", "pids"}) {
+		t.Fatalf("unexpected v1 result %+v", got)
+	}
+
+	if got := collectHostCgroup(t.TempDir()); got != nil {
+		t.Fatalf("expected nil without /sys/fs/cgroup, got %+v", got)
+	}
+}
@@ -3,6 +3,7 @@ package collector
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/dashu-baba/docker-doctor/internal/config"
@@ -25,6 +26,7 @@ func Collect(ctx context.Context, apiVersion string, cfg *config.Config) (*types
 		return nil, fmt.Errorf("failed to collect host info: %w", err)
 	}
 	report.Host = *hostInfo
+	report.Host.Sysctls = collectSysctls("/proc", sysctlNames(cfg.Rules.Sysctl.Minimums))
 	if log != nil {
 		log.Printf("collector host: ok (%dms)", time.Since(hostStart).Milliseconds())
 	}
@@ -36,12 +38,24 @@ func Collect(ctx context.Context, apiVersion string, cfg *config.Config) (*types
 		return nil, fmt.Errorf("failed to collect Docker info: %w", err)
 	}
 	report.Docker = *dockerInfo
+	if report.Docker.CgroupVersion == "" && report.Host.Cgroup != nil {
+		// Daemons older than API 1.40 do not report the cgroup version.
+		report.Docker.CgroupVersion = report.Host.Cgroup.Version
+		report.Docker.CgroupSource = "host"
+	}
+
+	// Full mode: daemon configuration and host routes (host filesystem and /proc required)
+	if cfg.Scan.Mode == "full" {
+		report.Docker.DaemonConfig = collectDaemonConfig("/proc")
+		report.Host.Routes = collectRoutes("/proc")
+	}
 
-	containers, usedVolumes, err := collectContainers(ctx, cfg.Scan.DockerHost, apiVersion)
+	containers, usedVolumes, err := collectContainers(ctx, cfg.Scan.DockerHost, apiVersion, envHashKey(cfg.Rules.SecretEnv.HashKeyEnv))
 	if err != nil {
 		return nil, fmt.Errorf("failed to collect containers: %w", err)
 	}
 	report.Containers = *containers
+	report.Host.DiskUsage = collectDiskUsage(monitoredPaths(cfg, report.Containers.List), readMountinfo())
 
 	images, err := collectImages(ctx, cfg.Scan.DockerHost, apiVersion)
 	if err != nil {
@@ -76,6 +90,47 @@ func Collect(ctx context.Context, apiVersion string, cfg *config.Config) (*types
 		}
 	}
 
+	mergeWritableLayers(report.Containers.List, df)
+	if wl := cfg.Rules.WritableLayer; wl.Enabled && wl.DiffSample {
+		n := wl.SampleSize
+		if n <= 0 {
+			n = 5
+		}
+		// Only flagged containers are diffed; `docker diff` walks the whole writable layer.
+		for i := range report.Containers.List {
+			c := &report.Containers.List[i]
+			if c.SizeRw <= 0 || uint64(c.SizeRw) <= wl.SizeThreshold {
+				continue
+			}
+			paths, err := sampleContainerDiff(ctx, cfg.Scan.DockerHost, apiVersion, c.ID, n)
+			if err != nil {
+				if log != nil {
+					log.Printf("collector container_diff %s: %v", c.ID, err)
+				}
+				continue
+			}
+			c.WritableTopPaths = paths
+		}
+	}
+
+	// Optional: stream container stats for the sampling window
+	if cfg.Scan.Sample > 0 {
+		sampleStart := time.Now()
+		failed, err := sampleContainerStats(ctx, cfg.Scan.DockerHost, apiVersion, report.Containers.List, cfg.Scan.Sample)
+		if err != nil {
+			failed = []string{err.Error()}
+		}
+		if len(failed) > 0 {
+			// Resource rules skip containers without a sample; keep the gap visible in the report.
+			report.CollectorErrors = map[string][]string{"container_stats": failed}
+			if log != nil {
+				log.Printf("collector container_stats: %d error(s): %s", len(failed), strings.Join(failed, "; "))
+			}
+		} else if log != nil {
+			log.Printf("collector container_stats: ok (%dms)", time.Since(sampleStart).Milliseconds())
+		}
+	}
+
 	// Rules/diagnostics
 	rulesStart := time.Now()
 	rules.Evaluate(report, cfg, df)
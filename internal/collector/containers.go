@@ -6,15 +6,19 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
 
 	"github.com/dashu-baba/docker-doctor/internal/types"
 )
 
-func collectContainers(ctx context.Context, dockerHost string, apiVersion string) (*types.Containers, map[string]bool, error) {
+func collectContainers(ctx context.Context, dockerHost string, apiVersion string, envKey []byte) (*types.Containers, map[string]bool, error) {
 	cli, err := newClient(dockerHost, apiVersion)
 	if err != nil {
 		return nil, nil, err
@@ -48,27 +52,15 @@ func collectContainers(ctx context.Context, dockerHost string, apiVersion string
 			defer func() { <-sem }()
 
 			inspect, raw, err := cli.ContainerInspectWithRaw(ctx, c.ID, false)
-			oomKilled := false
-			healthStatus := "none"
-			var unhealthySince time.Time
-			restartCount := 0
-			if err == nil {
-				if inspect.State != nil {
-					oomKilled = inspect.State.OOMKilled
-				}
-				restartCount = inspect.RestartCount
-				healthStatus, unhealthySince = parseHealthFromInspectRaw(raw)
-
+			if err == nil && inspect.Mounts != nil {
 				// Collect used volumes (named volumes have Name set)
-				if inspect.Mounts != nil {
-					mu.Lock()
-					for _, mount := range inspect.Mounts {
-						if mount.Name != "" {
-							usedVolumes[mount.Name] = true
-						}
+				mu.Lock()
+				for _, mount := range inspect.Mounts {
+					if mount.Name != "" {
+						usedVolumes[mount.Name] = true
 					}
-					mu.Unlock()
 				}
+				mu.Unlock()
 			}
 
 			name := ""
@@ -76,21 +68,11 @@ func collectContainers(ctx context.Context, dockerHost string, apiVersion string
 				name = c.Names[0]
 			}
 
-			logSize := uint64(0)
-			if size, err := getContainerLogSize(c.ID); err == nil {
-				logSize = size
+			var ins *dtypes.ContainerJSON
+			if err == nil {
+				ins = &inspect
 			}
-
-			rows[i] = row{info: types.ContainerInfo{
-				ID:             c.ID[:12],
-				Name:           name,
-				RestartCount:   restartCount,
-				Status:         c.Status,
-				OOMKilled:      oomKilled,
-				HealthStatus:   healthStatus,
-				UnhealthySince: unhealthySince,
-				LogSize:        logSize,
-			}}
+			rows[i] = row{info: buildContainerInfo(c.ID, name, c.Status, ins, raw, envKey)}
 		}(i, c)
 	}
 	wg.Wait()
@@ -110,6 +92,184 @@ func collectContainers(ctx context.Context, dockerHost string, apiVersion string
 	return cont, usedVolumes, nil
 }
 
+// InspectContainer returns the ContainerInfo for a single container, as collectContainers
+// would report it. Used by watch mode to re-evaluate one container without a full scan.
+// hashKeyEnv is rules.secret_env.hash_key_env.
+func InspectContainer(ctx context.Context, dockerHost string, apiVersion string, id string, hashKeyEnv string) (*types.ContainerInfo, error) {
+	cli, err := newClient(dockerHost, apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	inspect, raw, err := cli.ContainerInspectWithRaw(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	info := buildContainerInfo(inspect.ID, inspect.Name, statusFromState(inspect.State), &inspect, raw, envHashKey(hashKeyEnv))
+	return &info, nil
+}
+
+// buildContainerInfo maps list/inspect data onto ContainerInfo. inspect may be nil when
+// the inspect call failed; only list-level fields are populated then.
+func buildContainerInfo(id, name, status string, inspect *dtypes.ContainerJSON, raw []byte, envKey []byte) types.ContainerInfo {
+	oomKilled := false
+	healthStatus := "none"
+	var unhealthySince time.Time
+	restartCount := 0
+	imageID := ""
+	var limits *types.ResourceLimits
+	var mounts []types.Mount
+	var security *types.SecurityInfo
+	var sensitive []types.SensitiveEnvVar
+	var ports, configuredPorts []types.PortBinding
+	var logConfig *types.LogConfig
+	if inspect != nil {
+		for _, m := range inspect.Mounts {
+			mounts = append(mounts, types.Mount{Type: string(m.Type), Source: m.Source, Destination: m.Destination, Name: m.Name, RW: m.RW})
+		}
+		imageID = inspect.Image
+		if inspect.ContainerJSONBase != nil && inspect.HostConfig != nil {
+			limits = resourceLimits(inspect.HostConfig)
+			security = securityInfo(inspect)
+			configuredPorts = portBindings(inspect.HostConfig.PortBindings)
+			logConfig = &types.LogConfig{Driver: inspect.HostConfig.LogConfig.Type, Opts: inspect.HostConfig.LogConfig.Config}
+		}
+		if inspect.Config != nil {
+			sensitive = sensitiveEnv(inspect.Config.Env, envKey)
+		}
+		if inspect.NetworkSettings != nil {
+			ports = portBindings(inspect.NetworkSettings.Ports)
+		}
+		if inspect.State != nil {
+			oomKilled = inspect.State.OOMKilled
+		}
+		restartCount = inspect.RestartCount
+		healthStatus, unhealthySince = parseHealthFromInspectRaw(raw)
+	}
+
+	logSize := uint64(0)
+	if size, err := getContainerLogSize(id); err == nil {
+		logSize = size
+	}
+
+	shortID := id
+	if len(shortID) > 12 {
+		shortID = shortID[:12]
+	}
+
+	return types.ContainerInfo{
+		ID:              shortID,
+		Name:            name,
+		RestartCount:    restartCount,
+		Status:          status,
+		OOMKilled:       oomKilled,
+		HealthStatus:    healthStatus,
+		UnhealthySince:  unhealthySince,
+		LogSize:         logSize,
+		ImageID:         imageID,
+		Limits:          limits,
+		Mounts:          mounts,
+		Security:        security,
+		SensitiveEnv:    sensitive,
+		Ports:           ports,
+		ConfiguredPorts: configuredPorts,
+		LogConfig:       logConfig,
+	}
+}
+
+// securityInfo copies the security-relevant settings of an inspected container.
+func securityInfo(inspect *dtypes.ContainerJSON) *types.SecurityInfo {
+	hc := inspect.HostConfig
+	sec := &types.SecurityInfo{
+		Privileged:      hc.Privileged,
+		CapAdd:          append([]string(nil), hc.CapAdd...),
+		NetworkMode:     string(hc.NetworkMode),
+		PidMode:         string(hc.PidMode),
+		IpcMode:         string(hc.IpcMode),
+		SecurityOpt:     append([]string(nil), hc.SecurityOpt...),
+		AppArmorProfile: inspect.AppArmorProfile,
+		ReadonlyRootfs:  hc.ReadonlyRootfs,
+	}
+	if inspect.Config != nil {
+		sec.User = inspect.Config.User
+	}
+	return sec
+}
+
+// portBindings flattens the published ports of a container, sorted by container port,
+// protocol and host address. Exposed but unpublished ports are skipped.
+func portBindings(pm nat.PortMap) []types.PortBinding {
+	var out []types.PortBinding
+	for port, bindings := range pm {
+		for _, b := range bindings {
+			hostPort, err := strconv.Atoi(b.HostPort)
+			if err != nil {
+				continue
+			}
+			out = append(out, types.PortBinding{
+				HostIP:        b.HostIP,
+				HostPort:      hostPort,
+				ContainerPort: port.Int(),
+				Protocol:      port.Proto(),
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.ContainerPort != b.ContainerPort {
+			return a.ContainerPort < b.ContainerPort
+		}
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		if a.HostIP != b.HostIP {
+			return a.HostIP < b.HostIP
+		}
+		return a.HostPort < b.HostPort
+	})
+	return out
+}
+
+// resourceLimits copies the resource settings of a HostConfig.
+func resourceLimits(hc *container.HostConfig) *types.ResourceLimits {
+	l := &types.ResourceLimits{
+		Memory:     hc.Memory,
+		MemorySwap: hc.MemorySwap,
+		NanoCPUs:   hc.NanoCPUs,
+		CPUQuota:   hc.CPUQuota,
+		CPUPeriod:  hc.CPUPeriod,
+		CPUShares:  hc.CPUShares,
+	}
+	if hc.PidsLimit != nil {
+		l.PidsLimit = *hc.PidsLimit
+	}
+	for _, u := range hc.Ulimits {
+		if u != nil {
+			l.Ulimits = append(l.Ulimits, types.Ulimit{Name: u.Name, Soft: u.Soft, Hard: u.Hard})
+		}
+	}
+	return l
+}
+
+// statusFromState approximates the human status string of `docker ps` ("Up", "Restarting", "Exited (137)")
+// from inspect state, so status-based rules behave the same for inspected containers.
+func statusFromState(state *dtypes.ContainerState) string {
+	if state == nil {
+		return ""
+	}
+	switch {
+	case state.Restarting:
+		return fmt.Sprintf("Restarting (%d)", state.ExitCode)
+	case state.Running:
+		return "Up"
+	case state.Status == "exited":
+		return fmt.Sprintf("Exited (%d)", state.ExitCode)
+	case state.Status != "":
+		return strings.ToUpper(state.Status[:1]) + state.Status[1:]
+	}
+	return ""
+}
+
 func getContainerLogSize(containerID string) (uint64, error) {
 	// Try to read the log file size from /var/lib/docker/containers/<id>/<id>-json.log
 	logPath := filepath.Join("/var/lib/docker/containers", containerID, containerID+"-json.log")
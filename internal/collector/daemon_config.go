@@ -0,0 +1,244 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// defaultDaemonConfigPath is used when dockerd is not started with --config-file.
+const defaultDaemonConfigPath = "/etc/docker/daemon.json"
+
+// collectDaemonConfig reads the dockerd configuration: daemon.json (or the file given by
+// --config-file on the dockerd command line) with command-line flags applied on top.
+// A missing daemon.json only counts as readable when dockerd itself was found, since
+// the scanner may otherwise not see the host filesystem at all.
+func collectDaemonConfig(procRoot string) *types.DaemonConfig {
+	dc := &types.DaemonConfig{Path: defaultDaemonConfigPath, Source: "default"}
+	args, found := findDockerdCmdline(procRoot)
+	dc.DockerdFound = found
+	if p := flagValue(args, "--config-file"); p != "" {
+		dc.Path, dc.Source = p, "cmdline"
+	}
+
+	data, err := os.ReadFile(dc.Path)
+	switch {
+	case err == nil:
+		dc.Exists = true
+		dc.Readable = true
+		if err := parseDaemonJSON(data, dc); err != nil {
+			dc.ParseError = err.Error()
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		dc.Readable = found
+	}
+	applyDockerdFlags(args, dc)
+	return dc
+}
+
+// findDockerdCmdline returns the arguments of the first dockerd process in procRoot.
+func findDockerdCmdline(procRoot string) ([]string, bool) {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return nil, false
+	}
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join(procRoot, e.Name(), "comm"))
+		if err != nil || strings.TrimSpace(string(comm)) != "dockerd" {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(procRoot, e.Name(), "cmdline"))
+		if err != nil {
+			continue
+		}
+		args := strings.Split(strings.TrimRight(string(raw), "\x00"), "\x00")
+		if len(args) > 0 {
+			args = args[1:]
+		}
+		return args, true
+	}
+	return nil, false
+}
+
+// daemonJSON lists the daemon.json settings the rules check.
+type daemonJSON struct {
+	Hosts               []string                   `json:"hosts"`
+	TLS                 *bool                      `json:"tls"`
+	TLSVerify           *bool                      `json:"tlsverify"`
+	LogDriver           string                     `json:"log-driver"`
+	LogOpts             map[string]string          `json:"log-opts"`
+	LiveRestore         bool                       `json:"live-restore"`
+	UsernsRemap         string                     `json:"userns-remap"`
+	ICC                 *bool                      `json:"icc"`
+	DefaultUlimits      map[string]json.RawMessage `json:"default-ulimits"`
+	Bip                 string                     `json:"bip"`
+	DefaultAddressPools []types.AddressPool        `json:"default-address-pools"`
+}
+
+// parseDaemonJSON fills dc from the content of daemon.json. An empty file is valid.
+func parseDaemonJSON(data []byte, dc *types.DaemonConfig) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	for k := range keys {
+		dc.Keys = append(dc.Keys, k)
+	}
+	sort.Strings(dc.Keys)
+
+	var f daemonJSON
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("invalid value: %w", err)
+	}
+	dc.Hosts = f.Hosts
+	dc.TLS = f.TLS != nil && *f.TLS
+	dc.TLSVerify = f.TLSVerify != nil && *f.TLSVerify
+	dc.LogDriver = f.LogDriver
+	dc.LogOpts = f.LogOpts
+	dc.LiveRestore = f.LiveRestore
+	dc.UsernsRemap = f.UsernsRemap
+	dc.ICC = f.ICC
+	for name := range f.DefaultUlimits {
+		dc.DefaultUlimits = append(dc.DefaultUlimits, name)
+	}
+	sort.Strings(dc.DefaultUlimits)
+	dc.Bip = f.Bip
+	dc.AddressPools = f.DefaultAddressPools
+	return nil
+}
+
+// applyDockerdFlags applies the dockerd command-line flags the rules check. Flags take
+// "--flag=value" or "--flag value"; booleans are "--flag" or "--flag=false".
+func applyDockerdFlags(args []string, dc *types.DaemonConfig) {
+	valueFlags := map[string]bool{
+		"-H": true, "--host": true, "--config-file": true, "--log-driver": true, "--log-opt": true,
+		"--userns-remap": true, "--default-ulimit": true, "--bip": true, "--default-address-pool": true,
+	}
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if !strings.HasPrefix(name, "-") {
+			continue
+		}
+		if valueFlags[name] && !hasValue && i+1 < len(args) {
+			i++
+			value, hasValue = args[i], true
+		}
+		boolValue := !hasValue || value == "true" || value == "1"
+		switch name {
+		case "-H", "--host":
+			dc.Hosts = append(dc.Hosts, value)
+		case "--tls":
+			dc.TLS = boolValue
+		case "--tlsverify":
+			dc.TLSVerify = boolValue
+		case "--log-driver":
+			dc.LogDriver = value
+		case "--log-opt":
+			if k, v, ok := strings.Cut(value, "="); ok {
+				if dc.LogOpts == nil {
+					dc.LogOpts = map[string]string{}
+				}
+				dc.LogOpts[k] = v
+			}
+		case "--live-restore":
+			dc.LiveRestore = boolValue
+		case "--userns-remap":
+			dc.UsernsRemap = value
+		case "--icc":
+			icc := boolValue
+			dc.ICC = &icc
+		case "--default-ulimit":
+			if n, _, ok := strings.Cut(value, "="); ok {
+				dc.DefaultUlimits = append(dc.DefaultUlimits, n)
+			}
+		case "--bip":
+			dc.Bip = value
+		case "--default-address-pool":
+			pool := types.AddressPool{}
+			for _, kv := range strings.Split(value, ",") {
+				k, v, _ := strings.Cut(kv, "=")
+				switch k {
+				case "base":
+					pool.Base = v
+				case "size":
+					pool.Size, _ = strconv.Atoi(v)
+				}
+			}
+			dc.AddressPools = append(dc.AddressPools, pool)
+		}
+	}
+}
+
+// flagValue returns the value of a "--flag=value" or "--flag value" argument.
+func flagValue(args []string, flag string) string {
+	for i, a := range args {
+		if a == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+		if v, ok := strings.CutPrefix(a, flag+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// collectRoutes reads the IPv4 routing table of procRoot; the default route is skipped.
+func collectRoutes(procRoot string) []types.Route {
+	f, err := os.Open(filepath.Join(procRoot, "net", "route"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	return parseRoutes(f)
+}
+
+// parseRoutes parses /proc/net/route, whose Destination and Mask columns are
+// little-endian hex IPv4 addresses:
+//
+//	Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+//	eth0	0000A8C0	00000000	0001	0	0	0	00FFFFFF	0	0	0
+func parseRoutes(r io.Reader) []types.Route {
+	var out []types.Route
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[0] == "Iface" {
+			continue
+		}
+		dst, err1 := hex.DecodeString(fields[1])
+		mask, err2 := hex.DecodeString(fields[7])
+		if err1 != nil || err2 != nil || len(dst) != 4 || len(mask) != 4 {
+			continue
+		}
+		dst[0], dst[1], dst[2], dst[3] = dst[3], dst[2], dst[1], dst[0]
+		mask[0], mask[1], mask[2], mask[3] = mask[3], mask[2], mask[1], mask[0]
+		ones, _ := net.IPMask(mask).Size()
+		if ones == 0 {
+			continue
+		}
+		out = append(out, types.Route{
+			Iface:       fields[0],
+			Destination: fmt.Sprintf("%s/%d", net.IP(dst).String(), ones),
+		})
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func TestCollectDaemonConfig_CmdlineConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "custom.json")
+	daemonJSON := `{"log-opts": {"max-size": "10m"}, "live-restore": true, "icc": false, "default-ulimits": {"nofile": {"Name": "nofile", "Hard": 64000, "Soft": 64000}}}`
+	if err := os.WriteFile(cfgPath, []byte(daemonJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	proc := filepath.Join(dir, "proc")
+	for pid, comm := range map[string]string{"1": "systemd", "812": "dockerd"} {
+		if err := os.MkdirAll(filepath.Join(proc, pid), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(proc, pid, "comm"), []byte(comm+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmdline := strings.Join([]string{"/usr/bin/dockerd", "-H", "fd://", "-H=tcp://0.0.0.0:2375", "--config-file", cfgPath, "--userns-remap=default", "--default-address-pool", "base=10.10.0.0/16,size=24"}, "\x00") + "\x00"
+	if err := os.WriteFile(filepath.Join(proc, "812", "cmdline"), []byte(cmdline), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dc := collectDaemonConfig(proc)
+	if !dc.DockerdFound || !dc.Exists || !dc.Readable || dc.ParseError != "" || dc.Source != "cmdline" || dc.Path != cfgPath {
+		t.Fatalf("unexpected daemon config %+v", dc)
+	}
+	if !reflect.DeepEqual(dc.Hosts, []string{"fd://", "tcp://0.0.0.0:2375"}) {
+		t.Fatalf("expected hosts from the command line, got %v", dc.Hosts)
+	}
+	if !dc.LiveRestore || dc.ICC == nil || *dc.ICC || dc.UsernsRemap != "default" || dc.LogOpts["max-size"] != "10m" {
+		t.Fatalf("unexpected settings %+v", dc)
+	}
+	if !reflect.DeepEqual(dc.DefaultUlimits, []string{"nofile"}) || !reflect.DeepEqual(dc.AddressPools, []types.AddressPool{{Base: "10.10.0.0/16", Size: 24}}) {
+		t.Fatalf("unexpected ulimits/pools %+v", dc)
+	}
+}
+
+func TestParseDaemonJSON_Invalid(t *testing.T) {
+	for _, data := range []string{`{"live-restore": true,}`, `{"log-opts": {"max-file": 3}}`} {
+		if err := parseDaemonJSON([]byte(data), &types.DaemonConfig{}); err == nil {
+			t.Fatalf("%s: expected an error", data)
+		}
+	}
+	if err := parseDaemonJSON([]byte("\n"), &types.DaemonConfig{}); err != nil {
+		t.Fatalf("expected an empty file to be valid, got %v", err)
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	table := "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+		"eth0\t00000000\t0100A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n" +
+		"eth0\t0000A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t0\t0\t0\n" +
+		"docker0\t000011AC\t00000000\t0001\t0\t0\t0\t0000FFFF\t0\t0\t0\n"
+	want := []types.Route{{Iface: "eth0", Destination: "192.168.0.0/24"}, {Iface: "docker0", Destination: "172.17.0.0/16"}}
+	if got := parseRoutes(strings.NewReader(table)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,164 @@
+package collector
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// defaultDiskPaths are always monitored (when they exist).
+var defaultDiskPaths = []string{"/", "/var/lib/docker"}
+
+// monitoredPaths returns the paths whose filesystems are checked, in priority order:
+// the defaults, configured paths, then bind-mount sources of containers when discovery
+// is enabled. Duplicates are removed.
+func monitoredPaths(cfg *config.Config, containers []types.ContainerInfo) []string {
+	seen := map[string]bool{}
+	var out []string
+	add := func(p string) {
+		p = filepath.Clean(p)
+		if p == "" || !filepath.IsAbs(p) || seen[p] {
+			return
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	for _, p := range defaultDiskPaths {
+		add(p)
+	}
+	for _, p := range cfg.Rules.DiskUsage.Paths {
+		add(p.Path)
+	}
+	if cfg.Rules.DiskUsage.DiscoverBindMounts {
+		for _, c := range containers {
+			for _, m := range c.Mounts {
+				if m.Type == "bind" && m.Source != "" {
+					add(m.Source)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// collectDiskUsage reads byte/inode usage for each path. Paths living on a device that
+// was already seen (same st_dev) are not reported separately; they are listed in the
+// Paths of the first path on that device. Missing paths and pseudo filesystems are skipped.
+func collectDiskUsage(paths []string, mounts []mountEntry) map[string]*types.DiskInfo {
+	out := make(map[string]*types.DiskInfo)
+	byDev := map[uint64]*types.DiskInfo{}
+	for _, p := range paths {
+		var st syscall.Stat_t
+		if err := syscall.Stat(p, &st); err != nil {
+			continue
+		}
+		dev := uint64(st.Dev)
+		if first, ok := byDev[dev]; ok {
+			first.Paths = append(first.Paths, p)
+			continue
+		}
+		info, err := getDiskUsage(p)
+		if err != nil || info.Total == 0 {
+			continue
+		}
+		if m, ok := findMount(mounts, p); ok {
+			info.MountPoint = m.MountPoint
+			info.FSType = m.FSType
+		}
+		byDev[dev] = info
+		out[p] = info
+	}
+	return out
+}
+
+// mountEntry is one line of /proc/self/mountinfo.
+type mountEntry struct {
+	MountPoint string
+	FSType     string
+}
+
+// readMountinfo parses /proc/self/mountinfo; nil when it is not readable (non-Linux).
+func readMountinfo() []mountEntry {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	return parseMountinfo(f)
+}
+
+// parseMountinfo parses lines such as:
+//
+//	28 1 254:0 / / rw,relatime - ext4 /dev/vda rw,discard
+//
+// Optional fields between the mount options and "-" vary in number.
+func parseMountinfo(r io.Reader) []mountEntry {
+	var out []mountEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		sep := -1
+		for i, f := range fields {
+			if f == "-" {
+				sep = i
+				break
+			}
+		}
+		if len(fields) < 5 || sep < 0 || sep+1 >= len(fields) {
+			continue
+		}
+		out = append(out, mountEntry{MountPoint: unescapeMountinfo(fields[4]), FSType: fields[sep+1]})
+	}
+	return out
+}
+
+// unescapeMountinfo decodes the octal escapes (\040 for space, ...) used in mountinfo.
+func unescapeMountinfo(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// findMount returns the mount backing path: the longest mount point that is a prefix
+// of the resolved path. Later entries win on ties, since they are mounted on top.
+func findMount(mounts []mountEntry, path string) (mountEntry, bool) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	best, found := mountEntry{}, false
+	for _, m := range mounts {
+		if !pathWithin(path, m.MountPoint) {
+			continue
+		}
+		if !found || len(m.MountPoint) >= len(best.MountPoint) {
+			best, found = m, true
+		}
+	}
+	return best, found
+}
+
+func pathWithin(path, dir string) bool {
+	if dir == "/" {
+		return strings.HasPrefix(path, "/")
+	}
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
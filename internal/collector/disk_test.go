@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+const testMountinfo = `23 28 0:22 / /proc rw,relatime - proc proc rw
+28 1 254:0 / / rw,relatime - ext4 /dev/vda rw,discard
+40 28 254:16 / /srv/data rw,relatime shared:5 master:1 - xfs /dev/vdb rw
+41 28 254:32 / /mnt/with\040space rw,relatime - ext4 /dev/vdc rw
+`
+
+func TestParseMountinfoAndFindMount(t *testing.T) {
+	mounts := parseMountinfo(strings.NewReader(testMountinfo))
+	if len(mounts) != 4 {
+		t.Fatalf("expected 4 mounts, got %+v", mounts)
+	}
+	if mounts[3].MountPoint != "/mnt/with space" {
+		t.Fatalf("expected unescaped mount point, got %q", mounts[3].MountPoint)
+	}
+
+	for path, want := range map[string]string{
+		"/srv/data/pg":     "/srv/data|xfs",
+		"/srv/database":    "/|ext4",
+		"/var/lib/docker":  "/|ext4",
+		"/mnt/with space/": "/mnt/with space|ext4",
+	} {
+		m, ok := findMount(mounts, filepath.Clean(path))
+		if !ok || m.MountPoint+"|"+m.FSType != want {
+			t.Fatalf("%s: expected %s, got %+v (found=%v)", path, want, m, ok)
+		}
+	}
+}
+
+func TestMonitoredPaths(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{DiskUsage: config.DiskUsageRule{
+		Paths:              []config.DiskPath{{Path: "/var/log"}, {Path: "/"}},
+		DiscoverBindMounts: true,
+	}}}
+	containers := []types.ContainerInfo{{Mounts: []types.Mount{
+		{Type: "bind", Source: "/srv/data/"},
+		{Type: "volume", Source: "/var/lib/docker/volumes/x/_data", Name: "x"},
+		{Type: "bind", Source: "/var/log"},
+	}}}
+
+	got := monitoredPaths(cfg, containers)
+	want := []string{"/", "/var/lib/docker", "/var/log", "/srv/data"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	cfg.Rules.DiskUsage.DiscoverBindMounts = false
+	if got := monitoredPaths(cfg, containers); len(got) != 3 {
+		t.Fatalf("expected bind mounts to be ignored without discovery, got %v", got)
+	}
+}
+
+func TestCollectDiskUsage_DedupesByDevice(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	usage := collectDiskUsage([]string{dir, sub, filepath.Join(dir, "missing")}, nil)
+	if len(usage) != 1 || usage[dir] == nil {
+		t.Fatalf("expected a single entry for %s, got %v", dir, usage)
+	}
+	if !reflect.DeepEqual(usage[dir].Paths, []string{sub}) {
+		t.Fatalf("expected %s to be listed on the same filesystem, got %v", sub, usage[dir].Paths)
+	}
+}
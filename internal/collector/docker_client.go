@@ -11,3 +11,8 @@ func newClient(dockerHost string, apiVersion string) (*client.Client, error) {
 	return client.NewClientWithOpts(client.WithHost(dockerHost), client.WithVersion(apiVersion))
 }
 
+
+// NewClient returns a Docker API client for dockerHost (the default socket when empty).
+func NewClient(dockerHost string, apiVersion string) (*client.Client, error) {
+	return newClient(dockerHost, apiVersion)
+}
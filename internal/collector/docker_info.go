@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"strings"
 
 	"github.com/dashu-baba/docker-doctor/internal/types"
 )
@@ -32,14 +33,55 @@ func collectDockerInfo(ctx context.Context, dockerHost string, apiVersion string
 		"registry_config": info.RegistryConfig,
 	}
 
-	// Note: CgroupVersion and DockerRootDir may not be available in older API versions
-	// They will be empty in the struct, but can be populated from DaemonInfo if added later
+	// Note: DockerRootDir may not be available in older API versions.
+	// CgroupVersion needs API 1.40+; Collect falls back to /sys/fs/cgroup when it is empty.
+	cgroupSource := ""
+	if info.CgroupVersion != "" {
+		cgroupSource = "api"
+	}
 
 	return &types.DockerInfo{
 		Version:       version.Version,
-		CgroupVersion: "", // Not available in this API version
-		DataRoot:      "", // Not available in this API version
-		DaemonInfo:    daemonInfo,
+		CgroupVersion: info.CgroupVersion,
+		CgroupDriver:  info.CgroupDriver,
+		CgroupSource:  cgroupSource,
+		LimitSupport: &types.LimitSupport{
+			Memory:    info.MemoryLimit,
+			Swap:      info.SwapLimit,
+			Pids:      info.PidsLimit,
+			CPUQuota:  info.CPUCfsQuota,
+			CPUShares: info.CPUShares,
+			CPUSet:    info.CPUSet,
+		},
+		DataRoot:     "", // Not available in this API version
+		DaemonInfo:   daemonInfo,
+		MemTotal:     info.MemTotal,
+		NCPU:         info.NCPU,
+		Warnings:     daemonWarnings(info.Warnings),
+		DriverStatus: driverStatus(info.DriverStatus),
 	}, nil
 }
 
+// daemonWarnings trims the "WARNING: " prefix and surrounding whitespace of /info warnings.
+func daemonWarnings(in []string) []string {
+	var out []string
+	for _, w := range in {
+		w = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(w), "WARNING:"))
+		if w != "" {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// driverStatus converts the storage driver status pairs of /info into a map.
+func driverStatus(pairs [][2]string) map[string]string {
+	if len(pairs) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(pairs))
+	for _, kv := range pairs {
+		out[kv[0]] = kv[1]
+	}
+	return out
+}
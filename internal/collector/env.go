@@ -0,0 +1,124 @@
+package collector
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// secretNamePatterns map substrings of upper-cased variable names to a kind, checked in order.
+var secretNamePatterns = []struct {
+	substr, kind string
+}{
+	{"PRIVATE_KEY", "private_key"},
+	{"PASSWORD", "password"},
+	{"PASSWD", "password"},
+	{"API_KEY", "api_key"},
+	{"APIKEY", "api_key"},
+	{"ACCESS_KEY", "access_key"},
+	{"TOKEN", "token"},
+	{"SECRET", "secret"},
+	{"CREDENTIAL", "credential"},
+}
+
+// nonSecretAWSVars are AWS_* variables that only carry configuration.
+var nonSecretAWSVars = map[string]bool{
+	"AWS_REGION":                 true,
+	"AWS_DEFAULT_REGION":         true,
+	"AWS_PROFILE":                true,
+	"AWS_DEFAULT_OUTPUT":         true,
+	"AWS_ENDPOINT_URL":           true,
+	"AWS_SDK_LOAD_CONFIG":        true,
+	"AWS_STS_REGIONAL_ENDPOINTS": true,
+}
+
+// secretValuePatterns recognise well-known credential formats regardless of the variable name.
+var secretValuePatterns = []struct {
+	re   *regexp.Regexp
+	kind string
+}{
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`), "private_key"},
+	{regexp.MustCompile(`^eyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]*$`), "jwt"},
+	{regexp.MustCompile(`^(AKIA|ASIA)[0-9A-Z]{16}$`), "aws_access_key_id"},
+	{regexp.MustCompile(`^gh[pousr]_[A-Za-z0-9]{36,}$`), "github_token"},
+	{regexp.MustCompile(`^xox[abprs]-[A-Za-z0-9-]{10,}$`), "slack_token"},
+	{regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://[^/:@\s]+:[^/@\s]+@`), "url_credentials"},
+}
+
+var (
+	processKeyOnce sync.Once
+	processKey     []byte
+)
+
+// envHashKey returns the HMAC key for value fingerprints: the content of the environment
+// variable keyEnv when set, otherwise a random key generated once per process. An unkeyed
+// hash of a password is recoverable with a dictionary attack; a keyed one is not without the key.
+func envHashKey(keyEnv string) []byte {
+	if keyEnv != "" {
+		if k := os.Getenv(keyEnv); k != "" {
+			return []byte(k)
+		}
+	}
+	processKeyOnce.Do(func() {
+		processKey = make([]byte, 32)
+		if _, err := rand.Read(processKey); err != nil {
+			processKey = nil
+		}
+	})
+	return processKey
+}
+
+// sensitiveEnv classifies container environment variables ("NAME=value") that look like
+// secrets. Values never leave this function: only the name, the kind of match and a
+// truncated HMAC-SHA256 of the value under key are returned. Without a key no fingerprint
+// is produced.
+func sensitiveEnv(env []string, key []byte) []types.SensitiveEnvVar {
+	var out []types.SensitiveEnvVar
+	for _, kv := range env {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || name == "" || value == "" {
+			continue
+		}
+		kind, matchedBy := classifyEnv(name, value)
+		if kind == "" {
+			continue
+		}
+		v := types.SensitiveEnvVar{Name: name, Kind: kind, MatchedBy: matchedBy}
+		if len(key) > 0 {
+			mac := hmac.New(sha256.New, key)
+			mac.Write([]byte(value))
+			v.ValueHMAC = hex.EncodeToString(mac.Sum(nil))[:16]
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func classifyEnv(name, value string) (kind, matchedBy string) {
+	for _, p := range secretValuePatterns {
+		if p.re.MatchString(value) {
+			return p.kind, "value"
+		}
+	}
+	upper := strings.ToUpper(name)
+	// *_FILE points at a mounted secret (the Docker secrets convention) and *_PATH at a file.
+	if strings.HasSuffix(upper, "_FILE") || strings.HasSuffix(upper, "_PATH") {
+		return "", ""
+	}
+	for _, p := range secretNamePatterns {
+		if strings.Contains(upper, p.substr) {
+			return p.kind, "name"
+		}
+	}
+	if strings.HasPrefix(upper, "AWS_") && !nonSecretAWSVars[upper] {
+		return "aws_credential", "name"
+	}
+	return "", ""
+}
@@ -0,0 +1,45 @@
+package collector
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// ContainerEvents subscribes to the Docker `/events` stream for the container events
+// watch mode cares about (die, start, oom, restart, health_status). When since is non-zero,
+// events after that time are replayed, so a reconnect does not lose events. The client
+// is closed once the stream stops, whether ctx is done or the stream failed.
+func ContainerEvents(ctx context.Context, dockerHost string, apiVersion string, since time.Time) (<-chan events.Message, <-chan error, error) {
+	cli, err := newClient(dockerHost, apiVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f := filters.NewArgs(
+		filters.Arg("type", "container"),
+		filters.Arg("event", "die"),
+		filters.Arg("event", "start"),
+		filters.Arg("event", "oom"),
+		filters.Arg("event", "restart"),
+		// Matched as a prefix by the daemon ("health_status: unhealthy").
+		filters.Arg("event", "health_status"),
+	)
+	opts := dtypes.EventsOptions{Filters: f}
+	if !since.IsZero() {
+		opts.Since = strconv.FormatInt(since.Unix(), 10)
+	}
+	msgs, streamErrs := cli.Events(ctx, opts)
+	errs := make(chan error, 1)
+	go func() {
+		// The stream goroutine sends exactly one error when it stops, ctx.Err() included.
+		err := <-streamErrs
+		cli.Close()
+		errs <- err
+	}()
+	return msgs, errs, nil
+}
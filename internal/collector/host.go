@@ -41,18 +41,16 @@ func collectHostInfo() (*types.HostInfo, error) {
 		DiskUsage:     make(map[string]*types.DiskInfo),
 	}
 
-	// Get disk usage for root
-	if diskInfo, err := getDiskUsage("/"); err == nil {
-		info.DiskUsage["/"] = diskInfo
-	}
+	// Disk usage is filled in by Collect once container mounts are known (see collectDiskUsage).
 
-	// Get disk usage for /var/lib/docker if exists
-	dockerPath := "/var/lib/docker"
-	if _, err := os.Stat(dockerPath); err == nil {
-		if diskInfo, err := getDiskUsage(dockerPath); err == nil {
-			info.DiskUsage[dockerPath] = diskInfo
-		}
+	// Best-effort: memory, load and pressure (Linux /proc only)
+	if res, err := collectHostResources("/proc"); err == nil {
+		info.Resources = res
+	}
+	if listeners, err := collectListeners("/proc"); err == nil {
+		info.Listeners = listeners
 	}
+	info.Cgroup = collectHostCgroup("/")
 
 	return info, nil
 }
@@ -116,10 +114,16 @@ func getDiskUsage(path string) (*types.DiskInfo, error) {
 	available := stat.Bavail * uint64(stat.Bsize)
 	used := total - available
 	usedPercent := float64(used) / float64(total) * 100
-	return &types.DiskInfo{
+	info := &types.DiskInfo{
 		Used:        used,
 		Total:       total,
 		UsedPercent: usedPercent,
-	}, nil
+	}
+	if stat.Files > 0 && stat.Ffree <= stat.Files {
+		info.InodesTotal = stat.Files
+		info.InodesUsed = stat.Files - stat.Ffree
+		info.InodesUsedPercent = float64(info.InodesUsed) / float64(info.InodesTotal) * 100
+	}
+	return info, nil
 }
 
@@ -0,0 +1,190 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// collectHostResources reads memory, swap, load, PSI and OOM counters from procRoot
+// (normally /proc). Only /proc/meminfo is required; the other files are best-effort.
+func collectHostResources(procRoot string) (*types.HostResources, error) {
+	f, err := os.Open(filepath.Join(procRoot, "meminfo"))
+	if err != nil {
+		return nil, err
+	}
+	res, err := parseMeminfo(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	// runtime.NumCPU is the scanner's CPU affinity, which --cpuset-cpus narrows;
+	// /proc/stat lists every online CPU of the host.
+	res.NCPU = runtime.NumCPU()
+	if f, err := os.Open(filepath.Join(procRoot, "stat")); err == nil {
+		if n := parseProcStatCPUs(f); n > 0 {
+			res.NCPU = n
+		}
+		f.Close()
+	}
+
+	if data, err := os.ReadFile(filepath.Join(procRoot, "loadavg")); err == nil {
+		res.Load1, res.Load5, res.Load15, _ = parseLoadavg(string(data))
+	}
+	for name, dst := range map[string]**types.Pressure{"cpu": &res.PSICPU, "memory": &res.PSIMemory, "io": &res.PSIIO} {
+		if f, err := os.Open(filepath.Join(procRoot, "pressure", name)); err == nil {
+			if p, err := parsePressure(f); err == nil {
+				*dst = p
+			}
+			f.Close()
+		}
+	}
+	if f, err := os.Open(filepath.Join(procRoot, "vmstat")); err == nil {
+		vm := parseVMStat(f)
+		f.Close()
+		res.OOMKills, res.PswpIn, res.PswpOut = vm["oom_kill"], vm["pswpin"], vm["pswpout"]
+	}
+	return res, nil
+}
+
+// parseMeminfo reads the fields of /proc/meminfo we need; values are reported in kB.
+func parseMeminfo(r io.Reader) (*types.HostResources, error) {
+	res := &types.HostResources{}
+	seenTotal := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) >= 3 && fields[2] == "kB" {
+			v *= 1024
+		}
+		switch strings.TrimSuffix(fields[0], ":") {
+		case "MemTotal":
+			res.MemTotal = v
+			seenTotal = true
+		case "MemAvailable":
+			res.MemAvailable = v
+		case "SwapTotal":
+			res.SwapTotal = v
+		case "SwapFree":
+			res.SwapFree = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !seenTotal {
+		return nil, fmt.Errorf("meminfo: MemTotal not found")
+	}
+	return res, nil
+}
+
+// parseLoadavg parses "0.32 0.18 0.15 3/72 14120".
+func parseLoadavg(s string) (load1, load5, load15 float64, err error) {
+	fields := strings.Fields(s)
+	if len(fields) < 3 {
+		return 0, 0, 0, fmt.Errorf("loadavg: unexpected format %q", s)
+	}
+	loads := make([]float64, 3)
+	for i := range loads {
+		if loads[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	return loads[0], loads[1], loads[2], nil
+}
+
+// parsePressure parses a PSI file:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//
+// The "full" line is missing for cpu on older kernels.
+func parsePressure(r io.Reader) (*types.Pressure, error) {
+	p := &types.Pressure{}
+	found := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var avg10, avg60, avg300 *float64
+		switch fields[0] {
+		case "some":
+			avg10, avg60, avg300 = &p.SomeAvg10, &p.SomeAvg60, &p.SomeAvg300
+		case "full":
+			avg10, avg60, avg300 = &p.FullAvg10, &p.FullAvg60, &p.FullAvg300
+		default:
+			continue
+		}
+		found = true
+		for _, kv := range fields[1:] {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				continue
+			}
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			switch k {
+			case "avg10":
+				*avg10 = f
+			case "avg60":
+				*avg60 = f
+			case "avg300":
+				*avg300 = f
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("pressure: no some/full lines")
+	}
+	return p, nil
+}
+
+// parseVMStat parses /proc/vmstat "name value" lines.
+func parseVMStat(r io.Reader) map[string]uint64 {
+	out := map[string]uint64{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			out[fields[0]] = v
+		}
+	}
+	return out
+}
+
+// parseProcStatCPUs counts the per-CPU "cpuN" lines of /proc/stat.
+func parseProcStatCPUs(r io.Reader) int {
+	n := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) > 3 && strings.HasPrefix(line, "cpu") && line[3] >= '0' && line[3] <= '9' {
+			n++
+		}
+	}
+	return n
+}
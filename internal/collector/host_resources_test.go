@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseMeminfo(t *testing.T) {
+	res, err := parseMeminfo(strings.NewReader("MemTotal:        2048 kB\nMemFree:  100 kB\nMemAvailable:    512 kB\nSwapTotal:  1024 kB\nSwapFree:   256 kB\nHugePages_Total:       0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MemTotal != 2048*1024 || res.MemAvailable != 512*1024 || res.SwapTotal != 1024*1024 || res.SwapFree != 256*1024 {
+		t.Fatalf("unexpected meminfo %+v", res)
+	}
+	if _, err := parseMeminfo(strings.NewReader("MemFree: 1 kB\n")); err == nil {
+		t.Fatalf("expected error without MemTotal")
+	}
+}
+
+func TestParsePressureAndLoadavg(t *testing.T) {
+	p, err := parsePressure(strings.NewReader("some avg10=1.50 avg60=12.25 avg300=3.00 total=123\nfull avg10=0.00 avg60=4.75 avg300=0.10 total=9\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.SomeAvg60 != 12.25 || p.FullAvg60 != 4.75 || p.SomeAvg10 != 1.5 || p.FullAvg300 != 0.1 {
+		t.Fatalf("unexpected pressure %+v", p)
+	}
+	l1, l5, l15, err := parseLoadavg("4.00 2.50 1.25 3/72 14120\n")
+	if err != nil || l1 != 4 || l5 != 2.5 || l15 != 1.25 {
+		t.Fatalf("unexpected loadavg %v %v %v %v", l1, l5, l15, err)
+	}
+}
+
+func TestCollectHostResources(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"meminfo":         "MemTotal: 1000 kB\nMemAvailable: 50 kB\nSwapTotal: 0 kB\nSwapFree: 0 kB\n",
+		"loadavg":         "8.00 6.00 4.00 1/2 3\n",
+		"vmstat":          "pswpin 10\npswpout 20\noom_kill 3\n",
+		"stat":            "cpu  10 0 10 100 0 0 0 0 0 0\ncpu0 5 0 5 50 0 0 0 0 0 0\ncpu1 5 0 5 50 0 0 0 0 0 0\ncpu3 0 0 0 0 0 0 0 0 0 0\nintr 1 2 3\n",
+		"pressure/memory": "some avg10=0.00 avg60=20.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=5.00 avg300=0.00 total=0\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := collectHostResources(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Load5 != 6 || res.NCPU != 3 || res.OOMKills != 3 || res.PswpOut != 20 || res.PSIMemory == nil || res.PSIMemory.SomeAvg60 != 20 {
+		t.Fatalf("unexpected resources %+v", res)
+	}
+	if res.PSICPU != nil || res.PSIIO != nil {
+		t.Fatalf("expected missing PSI files to be skipped, got %+v", res)
+	}
+}
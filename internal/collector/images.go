@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"time"
 
 	dtypes "github.com/docker/docker/api/types"
 
@@ -14,7 +15,8 @@ func collectImages(ctx context.Context, dockerHost string, apiVersion string) (*
 		return nil, err
 	}
 
-	images, err := cli.ImageList(ctx, dtypes.ImageListOptions{All: true})
+	// SharedSize is only honoured on API >= 1.42; rules fill gaps from /system/df.
+	images, err := cli.ImageList(ctx, dtypes.ImageListOptions{All: true, SharedSize: true, ContainerCount: true})
 	if err != nil {
 		return nil, err
 	}
@@ -27,8 +29,14 @@ func collectImages(ctx context.Context, dockerHost string, apiVersion string) (*
 	for _, i := range images {
 		size := uint64(i.Size)
 		img.List = append(img.List, types.ImageInfo{
-			ID:   i.ID,
-			Size: size,
+			ID:          i.ID,
+			Size:        size,
+			RepoTags:    i.RepoTags,
+			RepoDigests: i.RepoDigests,
+			ParentID:    i.ParentID,
+			Created:     time.Unix(i.Created, 0).UTC(),
+			Containers:  i.Containers,
+			SharedSize:  i.SharedSize,
 		})
 		if size > 0 {
 			img.TotalSize += size
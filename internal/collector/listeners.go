@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// collectListeners returns the TCP listening sockets and bound UDP sockets of the
+// network namespace behind procRoot (/proc/net/{tcp,tcp6,udp,udp6}), sorted and
+// deduplicated. It returns an error only when none of the files can be read.
+func collectListeners(procRoot string) ([]types.Listener, error) {
+	seen := map[types.Listener]bool{}
+	var out []types.Listener
+	read := 0
+	for _, name := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		f, err := os.Open(filepath.Join(procRoot, "net", name))
+		if err != nil {
+			continue
+		}
+		read++
+		for _, l := range parseProcNet(f, strings.TrimSuffix(name, "6")) {
+			if !seen[l] {
+				seen[l] = true
+				out = append(out, l)
+			}
+		}
+		f.Close()
+	}
+	if read == 0 {
+		return nil, fmt.Errorf("no readable %s/net/{tcp,udp} files", procRoot)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		return a.Address < b.Address
+	})
+	return out, nil
+}
+
+// parseProcNet parses a /proc/net/{tcp,udp}[6] table, e.g.
+//
+//	sl  local_address rem_address   st ...
+//	0: 00000000:1F90 00000000:0000 0A ...
+//
+// Only TCP sockets in LISTEN (0A) and UDP sockets in the unconnected state (07) are kept.
+func parseProcNet(r io.Reader, proto string) []types.Listener {
+	wantState := "0A"
+	if proto == "udp" {
+		wantState = "07"
+	}
+	var out []types.Listener
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != wantState {
+			continue
+		}
+		addrHex, portHex, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		port, err := strconv.ParseUint(portHex, 16, 16)
+		if err != nil || port == 0 {
+			continue
+		}
+		addr, err := decodeProcNetAddr(addrHex)
+		if err != nil {
+			continue
+		}
+		out = append(out, types.Listener{Protocol: proto, Address: addr, Port: int(port)})
+	}
+	return out
+}
+
+// decodeProcNetAddr decodes the kernel's hex address: an IPv4 address or four 32-bit
+// words of an IPv6 address, each in host (little-endian) byte order.
+func decodeProcNetAddr(s string) (string, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || (len(b) != 4 && len(b) != 16) {
+		return "", fmt.Errorf("invalid address %q", s)
+	}
+	for i := 0; i < len(b); i += 4 {
+		b[i], b[i+1], b[i+2], b[i+3] = b[i+3], b[i+2], b[i+1], b[i]
+	}
+	return net.IP(b).String(), nil
+}
@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+const testProcNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:0CEA 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0
+   1: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:0CEA 0100007F:D2F0 01 00000000:00000000 00:00000000 00000000     0        0 1003 1 0000000000000000 20 4 30 10 -1
+`
+
+const testProcNetTCP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:1F90 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 2001 1 0000000000000000 100 0 0 10 0
+   1: 00000000000000000000000001000000:0016 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 2002 1 0000000000000000 100 0 0 10 0
+`
+
+func TestParseProcNet(t *testing.T) {
+	got := parseProcNet(strings.NewReader(testProcNetTCP), "tcp")
+	want := []types.Listener{
+		{Protocol: "tcp", Address: "127.0.0.1", Port: 3306},
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 8080},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestCollectListeners(t *testing.T) {
+	proc := t.TempDir()
+	if err := os.Mkdir(filepath.Join(proc, "net"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"tcp": testProcNetTCP, "tcp6": testProcNetTCP6} {
+		if err := os.WriteFile(filepath.Join(proc, "net", name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := collectListeners(proc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.Listener{
+		{Protocol: "tcp", Address: "::1", Port: 22},
+		{Protocol: "tcp", Address: "127.0.0.1", Port: 3306},
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 8080},
+		{Protocol: "tcp", Address: "::", Port: 8080},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	if _, err := collectListeners(t.TempDir()); err == nil {
+		t.Fatal("expected an error without /proc/net files")
+	}
+}
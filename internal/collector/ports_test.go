@@ -0,0 +1,26 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func TestPortBindings(t *testing.T) {
+	pm := nat.PortMap{
+		"6379/tcp": {{HostIP: "0.0.0.0", HostPort: "6379"}, {HostIP: "::", HostPort: "6379"}},
+		"80/tcp":   {{HostIP: "127.0.0.1", HostPort: "8080"}},
+		"9000/tcp": nil, // exposed, not published
+	}
+	want := []types.PortBinding{
+		{HostIP: "127.0.0.1", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{HostIP: "0.0.0.0", HostPort: 6379, ContainerPort: 6379, Protocol: "tcp"},
+		{HostIP: "::", HostPort: 6379, ContainerPort: 6379, Protocol: "tcp"},
+	}
+	if got := portBindings(pm); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,162 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// sampleContainerStats streams stats of running containers for the given window and
+// stores a summary on each container. All streams run at once so the whole sampling
+// takes one window regardless of the container count. Sampling is best-effort like the
+// other optional collectors: containers left without Stats are returned as errors.
+func sampleContainerStats(ctx context.Context, dockerHost string, apiVersion string, containers []types.ContainerInfo, window time.Duration) ([]string, error) {
+	cli, err := newClient(dockerHost, apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	var (
+		mu     sync.Mutex
+		failed []string
+		wg     sync.WaitGroup
+	)
+	fail := func(c *types.ContainerInfo, reason string) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, fmt.Sprintf("%s (%s): %s", strings.TrimPrefix(c.Name, "/"), c.ID, reason))
+	}
+	for i := range containers {
+		if !strings.HasPrefix(containers[i].Status, "Up") {
+			continue
+		}
+		wg.Add(1)
+		go func(c *types.ContainerInfo) {
+			defer wg.Done()
+
+			streamCtx, cancel := context.WithTimeout(ctx, window)
+			defer cancel()
+			resp, err := cli.ContainerStats(streamCtx, c.ID, true)
+			if err != nil {
+				fail(c, err.Error())
+				return
+			}
+			defer resp.Body.Close()
+
+			// The daemon sends one frame per second; the stream ends when the window elapses.
+			var frames []dtypes.StatsJSON
+			dec := json.NewDecoder(resp.Body)
+			for {
+				var f dtypes.StatsJSON
+				if err := dec.Decode(&f); err != nil {
+					break
+				}
+				frames = append(frames, f)
+			}
+			if c.Stats = summarizeStats(frames, c.Limits); c.Stats == nil {
+				fail(c, "no stats received within the sampling window")
+			}
+		}(&containers[i])
+	}
+	wg.Wait()
+	sort.Strings(failed)
+	return failed, nil
+}
+
+// summarizeStats reduces a stream of stats frames to a ResourceSample. CPU and throttling
+// are computed from the first and last frame (the counters are cumulative); memory and
+// PIDs keep the highest value seen. limits may be nil when they are unknown.
+func summarizeStats(frames []dtypes.StatsJSON, limits *types.ResourceLimits) *types.ResourceSample {
+	if len(frames) == 0 {
+		return nil
+	}
+	first, last := frames[0], frames[len(frames)-1]
+	s := &types.ResourceSample{
+		Samples:     len(frames),
+		MemoryLimit: last.MemoryStats.Limit,
+		PidsLimit:   last.PidsStats.Limit,
+	}
+	if !first.Read.IsZero() && last.Read.After(first.Read) {
+		s.DurationSeconds = last.Read.Sub(first.Read).Seconds()
+	}
+
+	s.CPUPercent = cpuPercent(first.CPUStats, last.CPUStats)
+	for i := 1; i < len(frames); i++ {
+		if p := cpuPercent(frames[i-1].CPUStats, frames[i].CPUStats); p > s.CPUPercentMax {
+			s.CPUPercentMax = p
+		}
+	}
+	if last.CPUStats.ThrottlingData.Periods >= first.CPUStats.ThrottlingData.Periods {
+		s.Periods = last.CPUStats.ThrottlingData.Periods - first.CPUStats.ThrottlingData.Periods
+	}
+	if last.CPUStats.ThrottlingData.ThrottledPeriods >= first.CPUStats.ThrottlingData.ThrottledPeriods {
+		s.ThrottledPeriods = last.CPUStats.ThrottlingData.ThrottledPeriods - first.CPUStats.ThrottlingData.ThrottledPeriods
+	}
+	if s.Periods > 0 {
+		s.ThrottledRatio = float64(s.ThrottledPeriods) / float64(s.Periods)
+	}
+
+	for _, f := range frames {
+		if m := workingSet(f.MemoryStats); m > s.MemoryUsage {
+			s.MemoryUsage = m
+		}
+		if f.PidsStats.Current > s.PidsCurrent {
+			s.PidsCurrent = f.PidsStats.Current
+		}
+	}
+
+	if limits != nil {
+		switch {
+		case limits.NanoCPUs > 0:
+			s.CPULimitCores = float64(limits.NanoCPUs) / 1e9
+		case limits.CPUQuota > 0:
+			period := limits.CPUPeriod
+			if period <= 0 {
+				period = 100000 // kernel default CFS period (100ms)
+			}
+			s.CPULimitCores = float64(limits.CPUQuota) / float64(period)
+		}
+		s.MemoryLimitSet = limits.Memory > 0
+		if s.PidsLimit == 0 && limits.PidsLimit > 0 {
+			s.PidsLimit = uint64(limits.PidsLimit)
+		}
+	}
+	return s
+}
+
+// cpuPercent is the CPU usage between two frames the way `docker stats` reports it:
+// 100 means one full core.
+func cpuPercent(prev, cur dtypes.CPUStats) float64 {
+	if cur.CPUUsage.TotalUsage < prev.CPUUsage.TotalUsage || cur.SystemUsage <= prev.SystemUsage {
+		return 0
+	}
+	online := float64(cur.OnlineCPUs)
+	if online == 0 {
+		online = float64(len(cur.CPUUsage.PercpuUsage))
+	}
+	cpuDelta := float64(cur.CPUUsage.TotalUsage - prev.CPUUsage.TotalUsage)
+	sysDelta := float64(cur.SystemUsage - prev.SystemUsage)
+	return cpuDelta / sysDelta * online * 100
+}
+
+// workingSet is memory usage minus inactive page cache, matching `docker stats`
+// (inactive_file on cgroup v2, total_inactive_file on cgroup v1).
+func workingSet(m dtypes.MemoryStats) uint64 {
+	inactive, ok := m.Stats["inactive_file"]
+	if !ok {
+		inactive = m.Stats["total_inactive_file"]
+	}
+	if inactive < m.Usage {
+		return m.Usage - inactive
+	}
+	return m.Usage
+}
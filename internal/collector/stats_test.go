@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func statsFrame(read time.Time, cpu, system, periods, throttled, mem, inactive, pids uint64) dtypes.StatsJSON {
+	var f dtypes.StatsJSON
+	f.Read = read
+	f.CPUStats.CPUUsage.TotalUsage = cpu
+	f.CPUStats.SystemUsage = system
+	f.CPUStats.OnlineCPUs = 4
+	f.CPUStats.ThrottlingData.Periods = periods
+	f.CPUStats.ThrottlingData.ThrottledPeriods = throttled
+	f.MemoryStats.Usage = mem
+	f.MemoryStats.Limit = 1000
+	f.MemoryStats.Stats = map[string]uint64{"inactive_file": inactive}
+	f.PidsStats.Current = pids
+	f.PidsStats.Limit = 100
+	return f
+}
+
+func TestSummarizeStats(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	frames := []dtypes.StatsJSON{
+		statsFrame(t0, 0, 0, 100, 10, 500, 100, 40),
+		statsFrame(t0.Add(time.Second), 100, 1000, 110, 15, 900, 50, 60),
+		statsFrame(t0.Add(2*time.Second), 150, 2000, 120, 20, 700, 0, 50),
+	}
+	s := summarizeStats(frames, &types.ResourceLimits{NanoCPUs: 1500000000, Memory: 1000, PidsLimit: 100})
+
+	if s.Samples != 3 || s.DurationSeconds != 2 {
+		t.Fatalf("unexpected window %+v", s)
+	}
+	// 150 of 2000 system ns on 4 CPUs = 30% average; the first second peaks at 40%.
+	if math.Abs(s.CPUPercent-30) > 1e-9 || math.Abs(s.CPUPercentMax-40) > 1e-9 {
+		t.Fatalf("unexpected cpu %v / %v", s.CPUPercent, s.CPUPercentMax)
+	}
+	if s.Periods != 20 || s.ThrottledPeriods != 10 || s.ThrottledRatio != 0.5 {
+		t.Fatalf("unexpected throttling %+v", s)
+	}
+	if s.MemoryUsage != 850 || s.MemoryLimit != 1000 || !s.MemoryLimitSet {
+		t.Fatalf("unexpected memory %+v", s)
+	}
+	if s.PidsCurrent != 60 || s.PidsLimit != 100 || s.CPULimitCores != 1.5 {
+		t.Fatalf("unexpected limits %+v", s)
+	}
+}
+
+func TestSummarizeStats_NoFramesOrLimits(t *testing.T) {
+	if summarizeStats(nil, nil) != nil {
+		t.Fatalf("expected nil sample without frames")
+	}
+	s := summarizeStats([]dtypes.StatsJSON{statsFrame(time.Time{}, 10, 10, 0, 0, 10, 0, 1)}, nil)
+	if s.CPUPercent != 0 || s.ThrottledRatio != 0 || s.MemoryLimitSet || s.CPULimitCores != 0 {
+		t.Fatalf("unexpected single-frame sample %+v", s)
+	}
+}
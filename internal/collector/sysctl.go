@@ -0,0 +1,58 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// defaultSysctls are the kernel parameters relevant to container hosts. net.* values
+// are per network namespace: they describe the host only when the scanner shares it.
+var defaultSysctls = []string{
+	"net.ipv4.ip_forward",
+	"net.bridge.bridge-nf-call-iptables",
+	"fs.inotify.max_user_watches",
+	"fs.inotify.max_user_instances",
+	"vm.max_map_count",
+	"fs.file-max",
+	"kernel.pid_max",
+	"vm.overcommit_memory",
+}
+
+// sysctlNames returns the default sysctls plus any configured in addition, sorted.
+func sysctlNames(configured map[string]int64) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, n := range defaultSysctls {
+		seen[n] = true
+		out = append(out, n)
+	}
+	for n := range configured {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// collectSysctls reads the named sysctls from procRoot/sys. Parameters that do not exist
+// (e.g. bridge-nf-call-iptables without the br_netfilter module) are left out; nil is
+// returned when /proc/sys is not readable at all.
+func collectSysctls(procRoot string, names []string) map[string]string {
+	if _, err := os.Stat(filepath.Join(procRoot, "sys")); err != nil {
+		return nil
+	}
+	out := map[string]string{}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(procRoot, "sys", filepath.FromSlash(strings.ReplaceAll(name, ".", "/"))))
+		if err != nil {
+			continue
+		}
+		// Multi-value parameters are tab separated; normalise to single spaces.
+		out[name] = strings.Join(strings.Fields(string(data)), " ")
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCollectSysctls(t *testing.T) {
+	proc := t.TempDir()
+	for name, content := range map[string]string{
+		"sys/net/ipv4/ip_forward":          "1\n",
+		"sys/vm/max_map_count":             "65530\n",
+		"sys/kernel/pid_max":               "4194304\n",
+		"sys/net/ipv4/ip_local_port_range": "32768\t60999\n",
+	} {
+		path := filepath.Join(proc, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := sysctlNames(map[string]int64{"net.ipv4.ip_local_port_range": 1, "vm.max_map_count": 262144})
+	if len(names) != len(defaultSysctls)+1 {
+		t.Fatalf("expected defaults plus one extra name, got %v", names)
+	}
+	got := collectSysctls(proc, names)
+	want := map[string]string{
+		"net.ipv4.ip_forward":          "1",
+		"vm.max_map_count":             "65530",
+		"kernel.pid_max":               "4194304",
+		"net.ipv4.ip_local_port_range": "32768 60999",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got := collectSysctls(filepath.Join(proc, "missing"), names); got != nil {
+		t.Fatalf("expected nil without /proc/sys, got %v", got)
+	}
+}
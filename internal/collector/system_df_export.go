@@ -16,19 +16,48 @@ import (
 
 type dockerSystemDFResponse struct {
 	LayersSize int64 `json:"LayersSize"`
+	Images     []struct {
+		ID         string   `json:"Id"`
+		RepoTags   []string `json:"RepoTags"`
+		Created    int64    `json:"Created"`
+		Size       int64    `json:"Size"`
+		SharedSize int64    `json:"SharedSize"`
+		Containers int64    `json:"Containers"`
+	} `json:"Images"`
 	Containers []struct {
-		SizeRw int64 `json:"SizeRw"`
+		ID      string   `json:"Id"`
+		Names   []string `json:"Names"`
+		ImageID string   `json:"ImageID"`
+		State   string   `json:"State"`
+		SizeRw  int64    `json:"SizeRw"`
+		Mounts  []struct {
+			Type string `json:"Type"`
+			Name string `json:"Name"`
+		} `json:"Mounts"`
 	} `json:"Containers"`
 	Volumes []struct {
+		Name      string            `json:"Name"`
+		Labels    map[string]string `json:"Labels"`
 		UsageData struct {
-			Size int64 `json:"Size"`
+			Size     int64 `json:"Size"`
+			RefCount int64 `json:"RefCount"`
 		} `json:"UsageData"`
 	} `json:"Volumes"`
 	BuildCache []struct {
-		Size int64 `json:"Size"`
+		ID         string     `json:"ID"`
+		Type       string     `json:"Type"`
+		Size       int64      `json:"Size"`
+		InUse      bool       `json:"InUse"`
+		Shared     bool       `json:"Shared"`
+		CreatedAt  time.Time  `json:"CreatedAt"`
+		LastUsedAt *time.Time `json:"LastUsedAt"`
+		UsageCount int        `json:"UsageCount"`
 	} `json:"BuildCache"`
 }
 
+// anonymousVolumeLabel marks volumes created without a name (Docker 23+).
+const anonymousVolumeLabel = "com.docker.volume.anonymous"
+
 // CollectDockerSystemDfSummary fetches `/system/df` and returns deduplicated totals.
 // Best-effort callers should treat errors as non-fatal.
 func CollectDockerSystemDfSummary(ctx context.Context, dockerHost string, apiVersion string) (*facts.DockerSystemDfSummary, error) {
@@ -121,6 +150,47 @@ func CollectDockerSystemDfSummary(ctx context.Context, dockerHost string, apiVer
 		VolumesTotalBytes:            uint64(max64(volumesTotal, 0)),
 		BuildCacheTotalBytes:         uint64(max64(buildCache, 0)),
 	}
+
+	for _, im := range df.Images {
+		out.Images = append(out.Images, facts.DfImage{
+			ID:         im.ID,
+			RepoTags:   im.RepoTags,
+			Created:    time.Unix(im.Created, 0).UTC(),
+			Size:       im.Size,
+			SharedSize: im.SharedSize,
+			Containers: im.Containers,
+		})
+	}
+	for _, c := range df.Containers {
+		dc := facts.DfContainer{ID: c.ID, Names: c.Names, ImageID: c.ImageID, State: c.State, SizeRw: c.SizeRw}
+		for _, m := range c.Mounts {
+			if m.Type == "volume" && m.Name != "" {
+				dc.Volumes = append(dc.Volumes, m.Name)
+			}
+		}
+		out.Containers = append(out.Containers, dc)
+	}
+	for _, v := range df.Volumes {
+		_, anon := v.Labels[anonymousVolumeLabel]
+		out.Volumes = append(out.Volumes, facts.DfVolume{
+			Name:      v.Name,
+			Size:      v.UsageData.Size,
+			RefCount:  v.UsageData.RefCount,
+			Anonymous: anon,
+		})
+	}
+	for _, b := range df.BuildCache {
+		out.BuildCache = append(out.BuildCache, facts.DfBuildCache{
+			ID:         b.ID,
+			Type:       b.Type,
+			Size:       b.Size,
+			InUse:      b.InUse,
+			Shared:     b.Shared,
+			CreatedAt:  b.CreatedAt,
+			LastUsedAt: b.LastUsedAt,
+			UsageCount: b.UsageCount,
+		})
+	}
 	return out, nil
 }
 
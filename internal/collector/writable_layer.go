@@ -0,0 +1,97 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/facts"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// mergeWritableLayers copies per-container SizeRw from /system/df into the container list.
+func mergeWritableLayers(containers []types.ContainerInfo, df *facts.DockerSystemDfSummary) {
+	if df == nil {
+		return
+	}
+	for i := range containers {
+		for _, c := range df.Containers {
+			if containers[i].ID != "" && strings.HasPrefix(c.ID, containers[i].ID) {
+				containers[i].SizeRw = c.SizeRw
+				break
+			}
+		}
+	}
+}
+
+// sampleContainerDiff groups the changes of a container's writable layer (`docker diff`)
+// by their first two path components and returns the n largest groups. Sizes are read
+// from the overlay upper directory when the host filesystem is accessible; otherwise
+// groups are ranked by number of changed entries.
+func sampleContainerDiff(ctx context.Context, dockerHost string, apiVersion string, id string, n int) ([]types.PathUsage, error) {
+	cli, err := newClient(dockerHost, apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	changes, err := cli.ContainerDiff(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	upperDir := ""
+	if inspect, err := cli.ContainerInspect(ctx, id); err == nil && inspect.GraphDriver.Data != nil {
+		upperDir = inspect.GraphDriver.Data["UpperDir"]
+	}
+
+	groups := map[string]*types.PathUsage{}
+	for _, ch := range changes {
+		// Kind 2 = deleted; deletions only leave whiteouts behind.
+		if ch.Kind == 2 {
+			continue
+		}
+		key := diffGroup(ch.Path)
+		g, ok := groups[key]
+		if !ok {
+			g = &types.PathUsage{Path: key}
+			groups[key] = g
+		}
+		g.Changes++
+		if upperDir != "" {
+			if st, err := os.Lstat(filepath.Join(upperDir, ch.Path)); err == nil && st.Mode().IsRegular() {
+				g.Bytes += uint64(st.Size())
+			}
+		}
+	}
+	return topPathUsage(groups, n), nil
+}
+
+func diffGroup(p string) string {
+	parts := strings.SplitN(strings.TrimPrefix(filepath.Clean(p), "/"), "/", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+func topPathUsage(groups map[string]*types.PathUsage, n int) []types.PathUsage {
+	out := make([]types.PathUsage, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Bytes != out[j].Bytes {
+			return out[i].Bytes > out[j].Bytes
+		}
+		if out[i].Changes != out[j].Changes {
+			return out[i].Changes > out[j].Changes
+		}
+		return out[i].Path < out[j].Path
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
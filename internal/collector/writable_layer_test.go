@@ -0,0 +1,34 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/dashu-baba/docker-doctor/internal/facts"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func TestMergeWritableLayers(t *testing.T) {
+	list := []types.ContainerInfo{{ID: "0123456789ab"}, {ID: "ffffffffffff"}}
+	df := &facts.DockerSystemDfSummary{Containers: []facts.DfContainer{{ID: "0123456789abcdef", SizeRw: 42}}}
+	mergeWritableLayers(list, df)
+	if list[0].SizeRw != 42 || list[1].SizeRw != 0 {
+		t.Fatalf("unexpected merge result %+v", list)
+	}
+}
+
+func TestTopPathUsage(t *testing.T) {
+	groups := map[string]*types.PathUsage{}
+	for _, p := range []string{"/var/lib/mysql/ibdata1", "/var/lib/mysql/log", "/tmp/x", "/var/cache/apt/a"} {
+		k := diffGroup(p)
+		if groups[k] == nil {
+			groups[k] = &types.PathUsage{Path: k}
+		}
+		groups[k].Changes++
+	}
+	groups["/tmp/x"].Bytes = 10
+
+	top := topPathUsage(groups, 2)
+	if len(top) != 2 || top[0].Path != "/tmp/x" || top[1].Path != "/var/lib" || top[1].Changes != 2 {
+		t.Fatalf("unexpected top paths %+v", top)
+	}
+}
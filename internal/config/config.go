@@ -2,16 +2,20 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the top-level configuration structure.
 type Config struct {
-	Scan  ScanConfig `yaml:"scan"`
-	Rules Rules      `yaml:"rules"`
+	Scan   ScanConfig   `yaml:"scan"`
+	Rules  Rules        `yaml:"rules"`
+	Notify NotifyConfig `yaml:"notify"`
 }
 
 // ScanConfig holds configuration for the scan operation.
@@ -20,23 +24,53 @@ type ScanConfig struct {
 	Timeout    int    `yaml:"timeout"`
 	DockerHost string `yaml:"dockerHost"`
 	Version    string `yaml:"version"`
+	// Sample, when set, streams container stats for this long during a scan (e.g. "30s").
+	Sample time.Duration `yaml:"sample"`
 }
 
 // Rules holds the diagnostic rules.
 type Rules struct {
-	DiskUsage    DiskUsageRule    `yaml:"disk_usage"`
-	StorageBloat StorageBloatRule `yaml:"storage_bloat"`
-	Restarts     RestartsRule     `yaml:"restarts"`
-	OOM          OOMRule          `yaml:"oom"`
-	Healthcheck  HealthcheckRule  `yaml:"healthcheck"`
-	LogBloat     LogBloatRule     `yaml:"log_bloat"`
-	VolumeBloat  VolumeBloatRule  `yaml:"volume_bloat"`
-	VolumeSize   VolumeSizeRule   `yaml:"volume_size"`
+	DiskUsage      DiskUsageRule      `yaml:"disk_usage"`
+	StorageBloat   StorageBloatRule   `yaml:"storage_bloat"`
+	Restarts       RestartsRule       `yaml:"restarts"`
+	OOM            OOMRule            `yaml:"oom"`
+	Healthcheck    HealthcheckRule    `yaml:"healthcheck"`
+	LogBloat       LogBloatRule       `yaml:"log_bloat"`
+	VolumeBloat    VolumeBloatRule    `yaml:"volume_bloat"`
+	VolumeSize     VolumeSizeRule     `yaml:"volume_size"`
+	BuildCache     BuildCacheRule     `yaml:"build_cache"`
+	Images         ImagesRule         `yaml:"images"`
+	WritableLayer  WritableLayerRule  `yaml:"writable_layer"`
+	Resources      ResourcesRule      `yaml:"resources"`
+	Limits         LimitsRule         `yaml:"limits"`
+	HostResources  HostResourcesRule  `yaml:"host_resources"`
+	InodeUsage     InodeUsageRule     `yaml:"inode_usage"`
+	Security       SecurityRule       `yaml:"security"`
+	SecretEnv      SecretEnvRule      `yaml:"secret_env"`
+	Ports          PortsRule          `yaml:"ports"`
+	PortConflict   PortConflictRule   `yaml:"port_conflict"`
+	DaemonConfig   DaemonConfigRule   `yaml:"daemon_config"`
+	DaemonWarnings DaemonWarningsRule `yaml:"daemon_warnings"`
+	StorageDriver  StorageDriverRule  `yaml:"storage_driver"`
+	EngineVersion  EngineVersionRule  `yaml:"engine_version"`
+	Sysctl         SysctlRule         `yaml:"sysctl"`
+	Cgroup         CgroupRule         `yaml:"cgroup"`
+	LogRotation    LogRotationRule    `yaml:"log_rotation"`
 }
 
 // DiskUsageRule defines rules for disk usage checks.
 type DiskUsageRule struct {
-	Threshold int `yaml:"threshold"` // percentage (0-100)
+	Threshold          int        `yaml:"threshold"`            // percentage (0-100)
+	Critical           int        `yaml:"critical"`             // percentage (0-100); 0 keeps the default severity scale
+	Paths              []DiskPath `yaml:"paths"`                // monitored in addition to / and /var/lib/docker
+	DiscoverBindMounts bool       `yaml:"discover_bind_mounts"` // also monitor bind-mount sources of containers
+}
+
+// DiskPath is a monitored path with optional thresholds overriding the rule defaults.
+type DiskPath struct {
+	Path     string `yaml:"path"`
+	Warning  int    `yaml:"warning"`  // percentage (0-100); 0 uses disk_usage.threshold
+	Critical int    `yaml:"critical"` // percentage (0-100); 0 uses disk_usage.critical
 }
 
 // StorageBloatRule defines rules for storage bloat checks.
@@ -78,6 +112,188 @@ type VolumeSizeRule struct {
 	SizeThreshold uint64 `yaml:"size_threshold"` // in bytes
 }
 
+// BuildCacheRule defines rules for build cache checks.
+type BuildCacheRule struct {
+	Enabled       bool   `yaml:"enabled"`
+	SizeThreshold uint64 `yaml:"size_threshold"` // in bytes
+	IdleDays      int    `yaml:"idle_days"`      // cache unused for longer is considered idle (0 = any unused cache)
+}
+
+// ImagesRule defines rules for dangling, unused and piled-up image checks.
+type ImagesRule struct {
+	Enabled       bool   `yaml:"enabled"`
+	UnusedDays    int    `yaml:"unused_days"`    // tagged images created more than this many days ago and unused by any container are reported
+	MaxVersions   int    `yaml:"max_versions"`   // tagged images kept per repository before reporting a pile-up (0 = off)
+	SizeThreshold uint64 `yaml:"size_threshold"` // reclaimable bytes above which findings are raised to medium
+}
+
+// WritableLayerRule defines rules for container writable layer checks.
+type WritableLayerRule struct {
+	Enabled       bool   `yaml:"enabled"`
+	SizeThreshold uint64 `yaml:"size_threshold"` // in bytes
+	DiffSample    bool   `yaml:"diff_sample"`    // sample `docker diff` of flagged containers for the largest changed paths
+	SampleSize    int    `yaml:"sample_size"`    // number of paths to keep (default 5)
+}
+
+// ResourcesRule defines rules for sampled container resource usage (requires scan.sample).
+type ResourcesRule struct {
+	Enabled        bool    `yaml:"enabled"`
+	ThrottledRatio float64 `yaml:"throttled_ratio"` // fraction (0-1) of CFS periods throttled
+	MemoryPercent  int     `yaml:"memory_percent"`  // memory usage vs limit (0-100)
+	PidsPercent    int     `yaml:"pids_percent"`    // pids vs pids limit (0-100)
+}
+
+// LimitsRule defines rules for container resource limits from HostConfig.
+type LimitsRule struct {
+	Enabled       bool    `yaml:"enabled"`
+	MaxOvercommit float64 `yaml:"max_overcommit"` // summed limits vs host RAM/CPUs (1.0 = 100%); 0 disables
+}
+
+// HostResourcesRule defines rules for host memory, swap and load (Linux /proc).
+type HostResourcesRule struct {
+	Enabled       bool    `yaml:"enabled"`
+	MemoryPercent int     `yaml:"memory_percent"` // used memory (MemTotal - MemAvailable) vs total (0-100)
+	SwapPercent   int     `yaml:"swap_percent"`   // used swap vs total (0-100)
+	LoadPerCPU    float64 `yaml:"load_per_cpu"`   // 5 minute load average per CPU
+	PSIThreshold  float64 `yaml:"psi_threshold"`  // PSI "some" avg60 percent; 0 disables PSI checks
+}
+
+// InodeUsageRule defines rules for filesystem inode usage.
+type InodeUsageRule struct {
+	Enabled           bool `yaml:"enabled"`
+	Threshold         int  `yaml:"threshold"`          // percentage (0-100)
+	CriticalThreshold int  `yaml:"critical_threshold"` // percentage (0-100); 0 means never critical
+}
+
+// SecurityRule defines the container security posture checks.
+type SecurityRule struct {
+	Enabled bool `yaml:"enabled"`
+	// IgnoreContainers lists container names (without "/") or ID prefixes that need
+	// elevated settings on purpose, e.g. monitoring agents or CI runners.
+	IgnoreContainers []string `yaml:"ignore_containers"`
+}
+
+// SecretEnvRule defines the check for secrets passed as container environment variables.
+type SecretEnvRule struct {
+	Enabled bool `yaml:"enabled"`
+	// IgnoreNames lists variable names that are known not to hold secrets (e.g. TOKEN_TTL).
+	IgnoreNames []string `yaml:"ignore_names"`
+	// HashKeyEnv names the environment variable holding the HMAC key for value fingerprints.
+	// Without it a random key is generated per process, so fingerprints only compare within one run.
+	HashKeyEnv string `yaml:"hash_key_env"`
+}
+
+// PortsRule defines the check for ports published on all host interfaces.
+type PortsRule struct {
+	Enabled bool `yaml:"enabled"`
+	// Allow lists intentionally public ports as "<port>[/<proto>]" for any container or
+	// "<container>:<port>[/<proto>]" for one container (name without "/"), e.g. "443", "web:8080/tcp".
+	Allow []string `yaml:"allow"`
+}
+
+// PortConflictRule defines the check for host ports claimed more than once.
+type PortConflictRule struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// DaemonConfigRule defines the daemon.json checks; they only run in full scan mode.
+type DaemonConfigRule struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// DaemonWarningsRule defines the check for warnings reported by the daemon in /info.
+type DaemonWarningsRule struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// StorageDriverRule defines the check for deprecated storage drivers and missing d_type.
+type StorageDriverRule struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// EngineVersionRule defines the Docker Engine lifecycle check.
+type EngineVersionRule struct {
+	Enabled bool `yaml:"enabled"`
+	// LifecycleFile replaces the embedded lifecycle table (same JSON format); empty uses the embedded one.
+	LifecycleFile string `yaml:"lifecycle_file"`
+	// MaxTableAgeDays flags a lifecycle table older than this many days; 0 disables.
+	MaxTableAgeDays int `yaml:"max_table_age_days"`
+}
+
+// SysctlRule defines recommended kernel parameters (/proc/sys) and kernel version checks.
+type SysctlRule struct {
+	Enabled bool `yaml:"enabled"`
+	// Minimums maps a sysctl name (e.g. "vm.max_map_count") to its recommended minimum; 0 disables a check.
+	Minimums map[string]int64 `yaml:"minimums"`
+	// OvercommitMemory lists the accepted vm.overcommit_memory values (0-2); empty disables the check.
+	OvercommitMemory []int `yaml:"overcommit_memory"`
+	// MinKernel is a kernel version floor (e.g. "4.19") on top of the storage driver and cgroup v2 requirements; empty disables.
+	MinKernel string `yaml:"min_kernel"`
+}
+
+// CgroupRule defines the cgroup version, driver and controller checks.
+type CgroupRule struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// LogRotationRule defines the per-container log rotation policy check.
+type LogRotationRule struct {
+	Enabled       bool     `yaml:"enabled"`
+	IgnoreDrivers []string `yaml:"ignore_drivers"` // log drivers whose retention is managed elsewhere (e.g. journald)
+}
+
+// PortAllow is a parsed PortsRule allow-list entry. Container and Protocol are empty
+// when the entry applies to any container or protocol.
+type PortAllow struct {
+	Container string
+	Port      int
+	Protocol  string
+}
+
+// ParsePortAllow parses an allow-list entry of PortsRule.
+func ParsePortAllow(entry string) (PortAllow, error) {
+	var a PortAllow
+	rest := strings.TrimSpace(entry)
+	if i := strings.LastIndex(rest, ":"); i >= 0 {
+		a.Container = strings.TrimPrefix(rest[:i], "/")
+		rest = rest[i+1:]
+		if a.Container == "" {
+			return a, fmt.Errorf("invalid port allow entry %q: empty container name", entry)
+		}
+	}
+	if p, proto, ok := strings.Cut(rest, "/"); ok {
+		rest = p
+		a.Protocol = strings.ToLower(proto)
+		if a.Protocol != "tcp" && a.Protocol != "udp" && a.Protocol != "sctp" {
+			return a, fmt.Errorf("invalid port allow entry %q: protocol must be tcp, udp or sctp", entry)
+		}
+	}
+	port, err := strconv.Atoi(rest)
+	if err != nil || port < 1 || port > 65535 {
+		return a, fmt.Errorf("invalid port allow entry %q: port must be between 1 and 65535", entry)
+	}
+	a.Port = port
+	return a, nil
+}
+
+// NotifyConfig holds post-scan notification settings.
+type NotifyConfig struct {
+	Webhooks []WebhookConfig `yaml:"webhooks"`
+}
+
+// WebhookConfig defines a single webhook target.
+type WebhookConfig struct {
+	Name      string `yaml:"name"`
+	URL       string `yaml:"url"`
+	Format    string `yaml:"format"`     // generic | slack | teams (default generic)
+	Secret    string `yaml:"secret"`     // HMAC-SHA256 key for the signature header
+	SecretEnv string `yaml:"secret_env"` // env var holding the secret (takes precedence over secret)
+	OnlyNew   bool   `yaml:"only_new"`   // notify only on new or worsened findings vs the previous scan
+	Template  string `yaml:"template"`   // optional text/template for slack/teams message text
+	Retries   int    `yaml:"retries"`    // retries after the first attempt
+	Timeout   int    `yaml:"timeout"`    // per-attempt timeout in seconds (default 10)
+}
+
 // Load reads and parses the config file.
 func Load(filename string) (*Config, error) {
 	data, err := os.ReadFile(filename)
@@ -102,7 +318,10 @@ func (c *Config) Validate() error {
 	if err := c.Scan.Validate(); err != nil {
 		return err
 	}
-	return c.Rules.Validate()
+	if err := c.Rules.Validate(); err != nil {
+		return err
+	}
+	return c.Notify.Validate()
 }
 
 // Validate checks the ScanConfig for correctness.
@@ -128,6 +347,10 @@ func (s *ScanConfig) Validate() error {
 		return fmt.Errorf("version cannot be empty")
 	}
 
+	if s.Sample < 0 {
+		return fmt.Errorf("sample must be non-negative, got %s", s.Sample)
+	}
+
 	return nil
 }
 
@@ -154,7 +377,61 @@ func (r *Rules) Validate() error {
 	if err := r.VolumeBloat.Validate(); err != nil {
 		return err
 	}
-	return r.VolumeSize.Validate()
+	if err := r.VolumeSize.Validate(); err != nil {
+		return err
+	}
+	if err := r.BuildCache.Validate(); err != nil {
+		return err
+	}
+	if err := r.Images.Validate(); err != nil {
+		return err
+	}
+	if err := r.WritableLayer.Validate(); err != nil {
+		return err
+	}
+	if err := r.Resources.Validate(); err != nil {
+		return err
+	}
+	if err := r.Limits.Validate(); err != nil {
+		return err
+	}
+	if err := r.HostResources.Validate(); err != nil {
+		return err
+	}
+	if err := r.InodeUsage.Validate(); err != nil {
+		return err
+	}
+	if err := r.Security.Validate(); err != nil {
+		return err
+	}
+	if err := r.SecretEnv.Validate(); err != nil {
+		return err
+	}
+	if err := r.Ports.Validate(); err != nil {
+		return err
+	}
+	if err := r.PortConflict.Validate(); err != nil {
+		return err
+	}
+	if err := r.DaemonConfig.Validate(); err != nil {
+		return err
+	}
+	if err := r.DaemonWarnings.Validate(); err != nil {
+		return err
+	}
+	if err := r.StorageDriver.Validate(); err != nil {
+		return err
+	}
+	if err := r.EngineVersion.Validate(); err != nil {
+		return err
+	}
+	if err := r.Sysctl.Validate(); err != nil {
+		return err
+	}
+	if err := r.Cgroup.Validate(); err != nil {
+		return err
+	}
+	return r.LogRotation.Validate()
 }
 
 // Validate checks the DiskUsageRule for correctness.
@@ -162,6 +439,20 @@ func (d *DiskUsageRule) Validate() error {
 	if d.Threshold < 0 || d.Threshold > 100 {
 		return fmt.Errorf("disk_usage threshold must be between 0 and 100, got %d", d.Threshold)
 	}
+	if d.Critical < 0 || d.Critical > 100 {
+		return fmt.Errorf("disk_usage critical must be between 0 and 100, got %d", d.Critical)
+	}
+	for i, p := range d.Paths {
+		if !strings.HasPrefix(p.Path, "/") {
+			return fmt.Errorf("disk_usage paths[%d]: path must be absolute, got %q", i, p.Path)
+		}
+		if p.Warning < 0 || p.Warning > 100 || p.Critical < 0 || p.Critical > 100 {
+			return fmt.Errorf("disk_usage paths[%d] (%s): thresholds must be between 0 and 100", i, p.Path)
+		}
+		if p.Warning > 0 && p.Critical > 0 && p.Critical < p.Warning {
+			return fmt.Errorf("disk_usage paths[%d] (%s): critical (%d) must not be below warning (%d)", i, p.Path, p.Critical, p.Warning)
+		}
+	}
 	return nil
 }
 
@@ -219,3 +510,238 @@ func (v *VolumeSizeRule) Validate() error {
 	}
 	return nil
 }
+
+// Validate checks the BuildCacheRule for correctness.
+func (b *BuildCacheRule) Validate() error {
+	if b.IdleDays < 0 {
+		return fmt.Errorf("build_cache idle_days must be non-negative, got %d", b.IdleDays)
+	}
+	return nil
+}
+
+// Validate checks the ImagesRule for correctness.
+func (i *ImagesRule) Validate() error {
+	if i.UnusedDays < 0 {
+		return fmt.Errorf("images unused_days must be non-negative, got %d", i.UnusedDays)
+	}
+	if i.MaxVersions < 0 {
+		return fmt.Errorf("images max_versions must be non-negative, got %d", i.MaxVersions)
+	}
+	return nil
+}
+
+// Validate checks the WritableLayerRule for correctness.
+func (w *WritableLayerRule) Validate() error {
+	if w.Enabled && w.SizeThreshold == 0 {
+		return fmt.Errorf("writable_layer size_threshold must be greater than 0 when the rule is enabled")
+	}
+	if w.SampleSize < 0 {
+		return fmt.Errorf("writable_layer sample_size must be non-negative, got %d", w.SampleSize)
+	}
+	return nil
+}
+
+// Validate checks the ResourcesRule for correctness.
+func (r *ResourcesRule) Validate() error {
+	if r.ThrottledRatio < 0 || r.ThrottledRatio > 1 {
+		return fmt.Errorf("resources throttled_ratio must be between 0 and 1, got %v", r.ThrottledRatio)
+	}
+	if r.MemoryPercent < 0 || r.MemoryPercent > 100 {
+		return fmt.Errorf("resources memory_percent must be between 0 and 100, got %d", r.MemoryPercent)
+	}
+	if r.PidsPercent < 0 || r.PidsPercent > 100 {
+		return fmt.Errorf("resources pids_percent must be between 0 and 100, got %d", r.PidsPercent)
+	}
+	return nil
+}
+
+// Validate checks the LimitsRule for correctness.
+func (r *LimitsRule) Validate() error {
+	if r.MaxOvercommit < 0 {
+		return fmt.Errorf("limits max_overcommit must be non-negative, got %v", r.MaxOvercommit)
+	}
+	return nil
+}
+
+// Validate checks the HostResourcesRule for correctness.
+func (r *HostResourcesRule) Validate() error {
+	if r.MemoryPercent < 0 || r.MemoryPercent > 100 {
+		return fmt.Errorf("host_resources memory_percent must be between 0 and 100, got %d", r.MemoryPercent)
+	}
+	if r.SwapPercent < 0 || r.SwapPercent > 100 {
+		return fmt.Errorf("host_resources swap_percent must be between 0 and 100, got %d", r.SwapPercent)
+	}
+	if r.LoadPerCPU < 0 {
+		return fmt.Errorf("host_resources load_per_cpu must be non-negative, got %v", r.LoadPerCPU)
+	}
+	if r.PSIThreshold < 0 || r.PSIThreshold > 100 {
+		return fmt.Errorf("host_resources psi_threshold must be between 0 and 100, got %v", r.PSIThreshold)
+	}
+	return nil
+}
+
+// Validate checks the InodeUsageRule for correctness.
+func (r *InodeUsageRule) Validate() error {
+	if r.Threshold < 0 || r.Threshold > 100 {
+		return fmt.Errorf("inode_usage threshold must be between 0 and 100, got %d", r.Threshold)
+	}
+	if r.CriticalThreshold < 0 || r.CriticalThreshold > 100 {
+		return fmt.Errorf("inode_usage critical_threshold must be between 0 and 100, got %d", r.CriticalThreshold)
+	}
+	if r.CriticalThreshold > 0 && r.CriticalThreshold < r.Threshold {
+		return fmt.Errorf("inode_usage critical_threshold (%d) must not be below threshold (%d)", r.CriticalThreshold, r.Threshold)
+	}
+	return nil
+}
+
+// Validate checks the SecurityRule for correctness.
+func (r *SecurityRule) Validate() error {
+	for i, c := range r.IgnoreContainers {
+		if strings.TrimSpace(c) == "" {
+			return fmt.Errorf("security ignore_containers[%d] cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+// Validate checks the SecretEnvRule for correctness.
+func (r *SecretEnvRule) Validate() error {
+	for i, n := range r.IgnoreNames {
+		if strings.TrimSpace(n) == "" {
+			return fmt.Errorf("secret_env ignore_names[%d] cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+// Validate checks the PortsRule for correctness.
+func (r *PortsRule) Validate() error {
+	for _, entry := range r.Allow {
+		if _, err := ParsePortAllow(entry); err != nil {
+			return fmt.Errorf("ports allow: %w", err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the PortConflictRule for correctness.
+func (r *PortConflictRule) Validate() error {
+	// No validation needed for boolean
+	return nil
+}
+
+// Validate checks the DaemonConfigRule for correctness.
+func (r *DaemonConfigRule) Validate() error {
+	// No validation needed for boolean
+	return nil
+}
+
+// Validate checks the DaemonWarningsRule for correctness.
+func (r *DaemonWarningsRule) Validate() error {
+	// No validation needed for boolean
+	return nil
+}
+
+// Validate checks the StorageDriverRule for correctness.
+func (r *StorageDriverRule) Validate() error {
+	// No validation needed for boolean
+	return nil
+}
+
+// Validate checks the EngineVersionRule for correctness.
+func (r *EngineVersionRule) Validate() error {
+	if r.MaxTableAgeDays < 0 {
+		return fmt.Errorf("engine_version max_table_age_days must be non-negative, got %d", r.MaxTableAgeDays)
+	}
+	return nil
+}
+
+// Validate checks the SysctlRule for correctness.
+func (r *SysctlRule) Validate() error {
+	for name, min := range r.Minimums {
+		if !validSysctlName(name) {
+			return fmt.Errorf("sysctl minimums: invalid sysctl name %q (expected dotted form, e.g. vm.max_map_count)", name)
+		}
+		if min < 0 {
+			return fmt.Errorf("sysctl minimums: %s must be non-negative, got %d", name, min)
+		}
+	}
+	for _, v := range r.OvercommitMemory {
+		if v < 0 || v > 2 {
+			return fmt.Errorf("sysctl overcommit_memory values must be 0, 1 or 2, got %d", v)
+		}
+	}
+	if r.MinKernel != "" {
+		for _, part := range strings.Split(r.MinKernel, ".") {
+			if _, err := strconv.Atoi(part); err != nil {
+				return fmt.Errorf("sysctl min_kernel must be a dotted version such as 4.19, got %q", r.MinKernel)
+			}
+		}
+	}
+	return nil
+}
+
+// validSysctlName reports whether name is a dotted sysctl name without path tricks.
+func validSysctlName(name string) bool {
+	parts := strings.Split(name, ".")
+	if len(parts) < 2 {
+		return false
+	}
+	for _, p := range parts {
+		if p == "" {
+			return false
+		}
+		for _, c := range p {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Validate checks the CgroupRule for correctness.
+func (r *CgroupRule) Validate() error {
+	// No validation needed for boolean
+	return nil
+}
+
+// Validate checks the LogRotationRule for correctness.
+func (r *LogRotationRule) Validate() error {
+	for i, d := range r.IgnoreDrivers {
+		if strings.TrimSpace(d) == "" {
+			return fmt.Errorf("log_rotation ignore_drivers[%d] cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+// Validate checks the NotifyConfig for correctness.
+func (n *NotifyConfig) Validate() error {
+	for i := range n.Webhooks {
+		if err := n.Webhooks[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks the WebhookConfig for correctness.
+func (w *WebhookConfig) Validate() error {
+	u, err := url.Parse(strings.TrimSpace(w.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook %q url must be an absolute http(s) URL, got '%s'", w.Name, w.URL)
+	}
+	switch strings.ToLower(w.Format) {
+	case "", "generic", "slack", "teams":
+	default:
+		return fmt.Errorf("webhook %q format must be one of: generic, slack, teams, got '%s'", w.Name, w.Format)
+	}
+	if w.Retries < 0 {
+		return fmt.Errorf("webhook %q retries must be non-negative, got %d", w.Name, w.Retries)
+	}
+	if w.Timeout < 0 {
+		return fmt.Errorf("webhook %q timeout must be non-negative, got %d", w.Name, w.Timeout)
+	}
+	return nil
+}
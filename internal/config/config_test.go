@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestConfigValidate(t *testing.T) {
@@ -124,6 +125,96 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid resources memory percent",
+			config: Config{
+				Scan: ScanConfig{
+					Mode:       "basic",
+					Timeout:    30,
+					DockerHost: "unix:///var/run/docker.sock",
+					Version:    "1.40",
+				},
+				Rules: Rules{
+					Resources: ResourcesRule{Enabled: true, MemoryPercent: 120},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid ports allow entry",
+			config: Config{
+				Scan: ScanConfig{
+					Mode:       "basic",
+					Timeout:    30,
+					DockerHost: "unix:///var/run/docker.sock",
+					Version:    "1.40",
+				},
+				Rules: Rules{
+					Ports: PortsRule{Enabled: true, Allow: []string{"443", "web:70000"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "enabled writable layer without size threshold",
+			config: Config{
+				Scan: ScanConfig{
+					Mode:       "basic",
+					Timeout:    30,
+					DockerHost: "unix:///var/run/docker.sock",
+					Version:    "1.40",
+				},
+				Rules: Rules{
+					WritableLayer: WritableLayerRule{Enabled: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative engine version table age",
+			config: Config{
+				Scan: ScanConfig{
+					Mode:       "basic",
+					Timeout:    30,
+					DockerHost: "unix:///var/run/docker.sock",
+					Version:    "1.40",
+				},
+				Rules: Rules{
+					EngineVersion: EngineVersionRule{Enabled: true, MaxTableAgeDays: -1},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid sysctl name",
+			config: Config{
+				Scan: ScanConfig{
+					Mode:       "basic",
+					Timeout:    30,
+					DockerHost: "unix:///var/run/docker.sock",
+					Version:    "1.40",
+				},
+				Rules: Rules{
+					Sysctl: SysctlRule{Enabled: true, Minimums: map[string]int64{"../../etc/shadow": 1}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid webhook format",
+			config: Config{
+				Scan: ScanConfig{
+					Mode:       "basic",
+					Timeout:    30,
+					DockerHost: "unix:///var/run/docker.sock",
+					Version:    "1.40",
+				},
+				Notify: NotifyConfig{
+					Webhooks: []WebhookConfig{{Name: "chat", URL: "https://hooks.example.com/x", Format: "irc"}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -143,6 +234,7 @@ func TestLoad(t *testing.T) {
   timeout: 30
   dockerHost: unix:///var/run/docker.sock
   version: "1.40"
+  sample: 30s
 rules:
   disk_usage:
     threshold: 80
@@ -184,7 +276,28 @@ rules:
 	if cfg.Scan.Version != "1.40" {
 		t.Errorf("Expected version '1.40', got %s", cfg.Scan.Version)
 	}
+	if cfg.Scan.Sample != 30*time.Second {
+		t.Errorf("Expected sample 30s, got %s", cfg.Scan.Sample)
+	}
 	if cfg.Rules.DiskUsage.Threshold != 80 {
 		t.Errorf("Expected disk_usage threshold 80, got %d", cfg.Rules.DiskUsage.Threshold)
 	}
 }
+
+func TestParsePortAllow(t *testing.T) {
+	for entry, want := range map[string]PortAllow{
+		"443":           {Port: 443},
+		"53/UDP":        {Port: 53, Protocol: "udp"},
+		"/web:8080/tcp": {Container: "web", Port: 8080, Protocol: "tcp"},
+	} {
+		got, err := ParsePortAllow(entry)
+		if err != nil || got != want {
+			t.Fatalf("%s: expected %+v, got %+v (err=%v)", entry, want, got, err)
+		}
+	}
+	for _, entry := range []string{"", "http", ":80", "80/icmp", "0"} {
+		if _, err := ParsePortAllow(entry); err == nil {
+			t.Fatalf("%q: expected an error", entry)
+		}
+	}
+}
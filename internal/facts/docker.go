@@ -1,5 +1,7 @@
 package facts
 
+import "time"
+
 // DockerSystemDfSummary is a simplified, deduplicated snapshot of Docker disk usage.
 // It mirrors the high-level numbers shown in `docker system df`.
 type DockerSystemDfSummary struct {
@@ -7,5 +9,56 @@ type DockerSystemDfSummary struct {
 	ContainersWritableTotalBytes uint64
 	VolumesTotalBytes            uint64
 	BuildCacheTotalBytes         uint64
+
+	// Per-object records from the same `/system/df` payload (as in `docker system df -v`).
+	Images     []DfImage
+	Containers []DfContainer
+	Volumes    []DfVolume
+	BuildCache []DfBuildCache
+}
+
+// DfImage is one image as reported by `/system/df`.
+type DfImage struct {
+	ID         string
+	RepoTags   []string
+	Created    time.Time
+	Size       int64
+	SharedSize int64 // -1 when not computed by the daemon
+	Containers int64 // number of containers using the image, -1 when not computed
+}
+
+// DfContainer is one container as reported by `/system/df`.
+type DfContainer struct {
+	ID      string
+	Names   []string
+	ImageID string
+	State   string // running | exited | created | paused | restarting | dead
+	SizeRw  int64
+	Volumes []string // names of volumes mounted by the container
 }
 
+// DfVolume is one volume as reported by `/system/df`.
+type DfVolume struct {
+	Name      string
+	Size      int64 // -1 when not computed
+	RefCount  int64 // containers referencing the volume, -1 when not computed
+	Anonymous bool
+}
+
+// DfBuildCache is one build cache record as reported by `/system/df`.
+type DfBuildCache struct {
+	ID         string
+	Type       string
+	Size       int64
+	InUse      bool
+	Shared     bool
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	UsageCount int
+}
+
+// KeptWithoutAll reports whether `docker builder prune` without --all leaves the record
+// alone: BuildKit only removes shared records and internal/frontend records with --all.
+func (b DfBuildCache) KeptWithoutAll() bool {
+	return b.Shared || b.Type == "internal" || b.Type == "frontend"
+}
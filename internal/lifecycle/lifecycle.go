@@ -0,0 +1,223 @@
+// Package lifecycle holds the Docker Engine release lifecycle table: release series,
+// end-of-life status and known issues fixed in specific patch releases.
+package lifecycle
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed lifecycle.json
+var embedded []byte
+
+// Table is the lifecycle data, either embedded in the binary or loaded from a file.
+type Table struct {
+	Updated     string       `json:"updated"` // YYYY-MM-DD
+	Source      string       `json:"source"`
+	Series      []Series     `json:"series"`
+	KnownIssues []KnownIssue `json:"known_issues"`
+}
+
+// Series is an Engine release line: "20.10", "25.0" or, since 26, a major version ("27").
+type Series struct {
+	Series      string `json:"series"`
+	APIVersion  string `json:"api_version"`
+	ReleaseDate string `json:"release_date"` // YYYY-MM
+	EOL         bool   `json:"eol"`
+	EOLDate     string `json:"eol_date,omitempty"`
+}
+
+// KnownIssue is a bug or vulnerability in some series, fixed in the listed patch releases.
+// Versions of an affected series without a fix in that series are always affected.
+type KnownIssue struct {
+	ID             string   `json:"id"`
+	Summary        string   `json:"summary"`
+	Severity       string   `json:"severity"` // low | medium | high
+	URL            string   `json:"url,omitempty"`
+	AffectedSeries []string `json:"affected_series"`
+	FixedIn        []string `json:"fixed_in,omitempty"`
+	AffectsRules   []string `json:"affects_rules,omitempty"` // rule IDs whose results the issue can skew
+}
+
+// Default returns the table embedded in the binary.
+func Default() (*Table, error) {
+	return parse(embedded)
+}
+
+// Load reads a lifecycle table from path, or returns the embedded one when path is empty.
+func Load(path string) (*Table, error) {
+	if path == "" {
+		return Default()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lifecycle file: %w", err)
+	}
+	t, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lifecycle file %s: %w", path, err)
+	}
+	return t, nil
+}
+
+func parse(data []byte) (*Table, error) {
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	if _, err := time.Parse("2006-01-02", t.Updated); err != nil {
+		return nil, fmt.Errorf("updated must be a YYYY-MM-DD date, got %q", t.Updated)
+	}
+	if len(t.Series) == 0 {
+		return nil, fmt.Errorf("no series defined")
+	}
+	for i, s := range t.Series {
+		if _, ok := parseVersion(s.Series); !ok {
+			return nil, fmt.Errorf("series[%d]: invalid series %q", i, s.Series)
+		}
+	}
+	for i, is := range t.KnownIssues {
+		if is.ID == "" {
+			return nil, fmt.Errorf("known_issues[%d]: id is required", i)
+		}
+		switch is.Severity {
+		case "low", "medium", "high":
+		default:
+			return nil, fmt.Errorf("known_issues[%d]: severity must be low, medium or high, got %q", i, is.Severity)
+		}
+	}
+	return &t, nil
+}
+
+// UpdatedAt returns the date of the table; zero when it cannot be parsed.
+func (t *Table) UpdatedAt() time.Time {
+	d, _ := time.Parse("2006-01-02", t.Updated)
+	return d
+}
+
+// Lookup returns the series of an engine version ("27.1.1", "24.0.9-ce").
+func (t *Table) Lookup(version string) (Series, bool) {
+	v, ok := parseVersion(version)
+	if !ok {
+		return Series{}, false
+	}
+	for _, s := range t.Series {
+		if inSeries(v, s.Series) {
+			return s, true
+		}
+	}
+	return Series{}, false
+}
+
+// OlderThanAll reports whether version predates every series in the table.
+func (t *Table) OlderThanAll(version string) bool {
+	if _, ok := parseVersion(version); !ok {
+		return false
+	}
+	for _, s := range t.Series {
+		if Compare(version, s.Series) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IssuesFor returns the known issues affecting an engine version.
+func (t *Table) IssuesFor(version string) []KnownIssue {
+	v, ok := parseVersion(version)
+	if !ok {
+		return nil
+	}
+	var out []KnownIssue
+	for _, is := range t.KnownIssues {
+		affected := false
+		for _, s := range is.AffectedSeries {
+			if !inSeries(v, s) {
+				continue
+			}
+			affected = true
+			for _, fix := range is.FixedIn {
+				if inSeries(mustParse(fix), s) && Compare(version, fix) >= 0 {
+					affected = false
+				}
+			}
+		}
+		if affected {
+			out = append(out, is)
+		}
+	}
+	return out
+}
+
+// Valid reports whether version is a dotted numeric version Compare can order.
+// Compare treats anything else as all zeros, so check Valid before trusting a result.
+func Valid(version string) bool {
+	_, ok := parseVersion(version)
+	return ok
+}
+
+// Compare compares two dotted versions numerically; suffixes such as "-ce" or
+// "+dfsg1" are ignored. Missing components count as 0.
+func Compare(a, b string) int {
+	va, _ := parseVersion(a)
+	vb, _ := parseVersion(b)
+	for i := 0; i < len(va) || i < len(vb); i++ {
+		var x, y int
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func inSeries(v []int, series string) bool {
+	s, ok := parseVersion(series)
+	if !ok || len(v) < len(s) {
+		return false
+	}
+	for i := range s {
+		if v[i] != s[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParse(version string) []int {
+	v, _ := parseVersion(version)
+	return v
+}
+
+// parseVersion parses the leading numeric components of a version string.
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+~ "); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, false
+	}
+	var out []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		out = append(out, n)
+	}
+	return out, true
+}
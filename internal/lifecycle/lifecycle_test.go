@@ -0,0 +1,100 @@
+package lifecycle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	table, err := Default()
+	if err != nil {
+		t.Fatalf("embedded table: %v", err)
+	}
+	if table.UpdatedAt().IsZero() || len(table.Series) == 0 {
+		t.Fatalf("unexpected embedded table: %+v", table)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"27.1.1", "27.1.1", 0},
+		{"27.1.0", "27.1.1", -1},
+		{"28", "27.5.1", 1},
+		{"20.10.24+dfsg1", "20.10.24", 0},
+		{"v24.0.9", "24.0.10", -1},
+	} {
+		if got := Compare(tc.a, tc.b); got != tc.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for version, want := range map[string]bool{
+		"27.1.1":         true,
+		"20.10.24+dfsg1": true,
+		"5.15.0":         true,
+		"6.6.31_1":       false,
+		"":               false,
+	} {
+		if got := Valid(version); got != want {
+			t.Errorf("Valid(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestLookupAndIssues(t *testing.T) {
+	table := &Table{
+		Series: []Series{{Series: "20.10", EOL: true}, {Series: "27"}},
+		KnownIssues: []KnownIssue{{
+			ID:             "BUG-1",
+			Severity:       "medium",
+			AffectedSeries: []string{"20.10", "27"},
+			FixedIn:        []string{"27.1.1"},
+		}},
+	}
+	if s, ok := table.Lookup("20.10.7-ce"); !ok || !s.EOL {
+		t.Fatalf("expected 20.10 EOL series, got %+v %v", s, ok)
+	}
+	if _, ok := table.Lookup("19.03.15"); ok {
+		t.Fatalf("19.03 is not in the table")
+	}
+	if !table.OlderThanAll("19.03.15") || table.OlderThanAll("30.0.0") {
+		t.Fatalf("OlderThanAll mismatch")
+	}
+	for version, want := range map[string]int{"20.10.24": 1, "27.0.3": 1, "27.1.1": 0, "27.3.0": 0, "28.0.0": 0} {
+		if got := len(table.IssuesFor(version)); got != want {
+			t.Errorf("IssuesFor(%q): expected %d issues, got %d", version, want, got)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "lifecycle.json")
+	if err := os.WriteFile(valid, []byte(`{"updated":"2030-01-01","series":[{"series":"40"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := Load(valid)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if table.Updated != "2030-01-01" || table.Series[0].Series != "40" {
+		t.Fatalf("unexpected table: %+v", table)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"updated":"2030-01-01","series":[{"series":"40"}],"known_issues":[{"id":"X","severity":"urgent"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(invalid); err == nil {
+		t.Fatalf("expected an error for an invalid severity")
+	}
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatalf("expected an error for a missing file")
+	}
+}
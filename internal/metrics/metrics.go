@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// Family is a single Prometheus metric family (HELP/TYPE header plus samples).
+type Family struct {
+	Name    string
+	Help    string
+	Type    string // gauge | counter
+	Samples []Sample
+}
+
+// Sample is one labelled value of a metric family.
+type Sample struct {
+	Labels [][2]string
+	Value  float64
+}
+
+// NewGauge returns an empty gauge family.
+func NewGauge(name, help string) *Family {
+	return &Family{Name: name, Help: help, Type: "gauge"}
+}
+
+// NewCounter returns an empty counter family.
+func NewCounter(name, help string) *Family {
+	return &Family{Name: name, Help: help, Type: "counter"}
+}
+
+// Add appends a sample. labels are key/value pairs: "path", "/", "severity", "warning", ...
+func (f *Family) Add(value float64, labels ...string) {
+	s := Sample{Value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		s.Labels = append(s.Labels, [2]string{labels[i], labels[i+1]})
+	}
+	f.Samples = append(f.Samples, s)
+}
+
+// FromScan builds the metric families describing a completed scan.
+// Both the v0 report (per-object detail) and the v1 report (findings, collectors) are used.
+func FromScan(v0 *types.Report, r *v1.Report) []*Family {
+	fams := []*Family{}
+
+	if r != nil {
+		findings := NewGauge("docker_doctor_findings", "Number of findings in the latest scan by rule and severity.")
+		counts := map[[2]string]int{}
+		for _, f := range r.Findings {
+			counts[[2]string{f.ID, f.Severity}]++
+		}
+		for k, n := range counts {
+			findings.Add(float64(n), "rule", k[0], "severity", k[1])
+		}
+		fams = append(fams, findings)
+
+		dur := NewGauge("docker_doctor_scan_duration_seconds", "Duration of the latest scan in seconds.")
+		dur.Add(float64(r.Scan.DurationMs) / 1000)
+		ts := NewGauge("docker_doctor_scan_timestamp_seconds", "Unix time the latest scan finished.")
+		ts.Add(float64(r.Scan.FinishedAt.Unix()))
+		fams = append(fams, dur, ts)
+
+		ok := NewGauge("docker_doctor_collector_success", "Whether the collector succeeded in the latest scan (1 = ok).")
+		for _, c := range r.Collectors {
+			v := 0.0
+			if c.Status == "ok" {
+				v = 1
+			}
+			ok.Add(v, "collector", c.Name, "status", c.Status)
+		}
+		fams = append(fams, ok)
+
+		df := NewGauge("docker_doctor_system_df_bytes", "Deduplicated Docker disk usage from /system/df by type.")
+		snap := r.Summary.ResourceSnapshot.DockerSystemDf
+		df.Add(float64(snap.ImagesTotalBytes), "type", "images")
+		df.Add(float64(snap.BuildCacheTotalBytes), "type", "build_cache")
+		df.Add(float64(snap.VolumesTotalBytes), "type", "volumes")
+		df.Add(float64(snap.ContainersWritableTotalBytes), "type", "containers_writable")
+		fams = append(fams, df)
+	}
+
+	if v0 != nil {
+		used := NewGauge("docker_doctor_disk_used_bytes", "Used bytes of the monitored filesystem.")
+		total := NewGauge("docker_doctor_disk_total_bytes", "Total bytes of the monitored filesystem.")
+		ratio := NewGauge("docker_doctor_disk_used_ratio", "Used fraction (0-1) of the monitored filesystem.")
+		inodes := NewGauge("docker_doctor_disk_inodes_used_ratio", "Used fraction (0-1) of the inodes of the monitored filesystem.")
+		for path, d := range v0.Host.DiskUsage {
+			if d == nil {
+				continue
+			}
+			used.Add(float64(d.Used), "path", path)
+			total.Add(float64(d.Total), "path", path)
+			ratio.Add(d.UsedPercent/100, "path", path)
+			if d.InodesTotal > 0 {
+				inodes.Add(d.InodesUsedPercent/100, "path", path)
+			}
+		}
+		fams = append(fams, used, total, ratio, inodes)
+
+		restarts := NewGauge("docker_doctor_container_restart_count", "Restart count reported by container inspect.")
+		logs := NewGauge("docker_doctor_container_log_bytes", "Size of the container json-file log in bytes (0 when not readable).")
+		health := NewGauge("docker_doctor_container_health_status", "Container healthcheck status (1 for the current status).")
+		writable := NewGauge("docker_doctor_container_writable_bytes", "Size of the container writable layer in bytes (from docker system df).")
+		for _, c := range v0.Containers.List {
+			name := strings.TrimPrefix(c.Name, "/")
+			restarts.Add(float64(c.RestartCount), "id", c.ID, "name", name)
+			logs.Add(float64(c.LogSize), "id", c.ID, "name", name)
+			writable.Add(float64(c.SizeRw), "id", c.ID, "name", name)
+			for _, st := range []string{"healthy", "unhealthy", "starting", "none"} {
+				v := 0.0
+				if c.HealthStatus == st {
+					v = 1
+				}
+				health.Add(v, "id", c.ID, "name", name, "status", st)
+			}
+		}
+		fams = append(fams, restarts, logs, health, writable)
+	}
+
+	return fams
+}
+
+// Encode writes families in the Prometheus text exposition format (version 0.0.4).
+// Samples are sorted by label values for stable, diff-friendly output.
+func Encode(w io.Writer, fams []*Family) error {
+	for _, f := range fams {
+		sort.SliceStable(f.Samples, func(i, j int) bool {
+			return labelKey(f.Samples[i].Labels) < labelKey(f.Samples[j].Labels)
+		})
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.Name, escapeHelp(f.Help), f.Name, f.Type); err != nil {
+			return err
+		}
+		for _, s := range f.Samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.Name, formatLabels(s.Labels), formatValue(s.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func labelKey(labels [][2]string) string {
+	parts := make([]string, 0, len(labels))
+	for _, l := range labels {
+		parts = append(parts, l[0]+"="+l[1])
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func formatLabels(labels [][2]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(labels))
+	for _, l := range labels {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, l[0], escapeLabel(l[1])))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeLabel(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func escapeHelp(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// WriteFile atomically writes families to path for node_exporter's textfile collector.
+// The content is written to a temp file in the same directory and renamed into place,
+// so the collector never observes a partially written file.
+func WriteFile(path string, fams []*Family) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op after a successful rename
+
+	if err := Encode(tmp, fams); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
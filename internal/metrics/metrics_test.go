@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func TestEncode_FromScan(t *testing.T) {
+	v0 := &types.Report{
+		Host: types.HostInfo{DiskUsage: map[string]*types.DiskInfo{
+			"/": {Used: 50, Total: 100, UsedPercent: 50},
+		}},
+		Containers: types.Containers{List: []types.ContainerInfo{
+			{ID: "abc123", Name: "/app", RestartCount: 4, HealthStatus: "unhealthy", LogSize: 2048},
+		}},
+	}
+	r := &v1.Report{
+		Scan:       v1.Scan{DurationMs: 1500, FinishedAt: time.Unix(1700000000, 0)},
+		Collectors: []v1.Collector{{Name: "docker_engine", Status: "ok"}, {Name: "host_fs", Status: "skipped"}},
+		Findings: []v1.Finding{
+			{ID: "RESTART_LOOP", Severity: "critical"},
+			{ID: "LOG_BLOAT", Severity: "warning"},
+			{ID: "LOG_BLOAT", Severity: "warning"},
+		},
+	}
+
+	var b strings.Builder
+	if err := Encode(&b, FromScan(v0, r)); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, needle := range []string{
+		"# TYPE docker_doctor_findings gauge",
+		`docker_doctor_findings{rule="LOG_BLOAT",severity="warning"} 2`,
+		`docker_doctor_findings{rule="RESTART_LOOP",severity="critical"} 1`,
+		"docker_doctor_scan_duration_seconds 1.5",
+		`docker_doctor_collector_success{collector="host_fs",status="skipped"} 0`,
+		`docker_doctor_disk_used_ratio{path="/"} 0.5`,
+		`docker_doctor_container_restart_count{id="abc123",name="app"} 4`,
+		`docker_doctor_container_health_status{id="abc123",name="app",status="unhealthy"} 1`,
+		`docker_doctor_system_df_bytes{type="images"} 0`,
+	} {
+		if !strings.Contains(out, needle) {
+			t.Fatalf("metrics output missing %q\n\n%s", needle, out)
+		}
+	}
+}
+
+func TestEncode_EscapesLabelValues(t *testing.T) {
+	g := NewGauge("x", "help")
+	g.Add(1, "path", "a\"b\\c\nd")
+	var b strings.Builder
+	if err := Encode(&b, []*Family{g}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `x{path="a\"b\\c\nd"} 1`) {
+		t.Fatalf("unexpected escaping:\n%s", b.String())
+	}
+}
+
+func TestWriteFile_Atomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docker_doctor.prom")
+	g := NewGauge("docker_doctor_findings", "help")
+	g.Add(3, "rule", "OOM_KILLED", "severity", "critical")
+
+	if err := WriteFile(path, []*Family{g}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `docker_doctor_findings{rule="OOM_KILLED",severity="critical"} 3`) {
+		t.Fatalf("unexpected file content:\n%s", data)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected only the .prom file to remain, got %d entries", len(entries))
+	}
+}
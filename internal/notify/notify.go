@@ -0,0 +1,313 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, prefixed with "sha256=".
+const SignatureHeader = "X-Docker-Doctor-Signature"
+
+const defaultTemplate = `*docker-doctor* scan {{.Report.Scan.ScanID}} on *{{.Host}}*: {{.Report.Summary.FindingCounts.Critical}} critical, {{.Report.Summary.FindingCounts.Warning}} warning, {{.Report.Summary.FindingCounts.Info}} info
+{{- if .NewFindings}}
+New or worsened findings:
+{{- range .NewFindings}}
+• [{{.Severity}}] {{.ID}}: {{.Summary}}
+{{- end}}
+{{- else}}
+No new or worsened findings.
+{{- end}}`
+
+// Payload is the generic JSON body sent to "generic" webhooks.
+type Payload struct {
+	Event         string       `json:"event"`
+	SchemaVersion string       `json:"schemaVersion"`
+	ScanID        string       `json:"scanId"`
+	FinishedAt    time.Time    `json:"finishedAt"`
+	Host          string       `json:"host"`
+	PreviousScan  string       `json:"previousScanId,omitempty"`
+	Summary       v1.Summary   `json:"summary"`
+	NewFindings   []v1.Finding `json:"newFindings"`
+}
+
+// Notifier delivers scan results to webhooks.
+type Notifier struct {
+	Client *http.Client
+	// Backoff returns the delay before retry attempt n (1-based). Defaults to exponential backoff.
+	Backoff func(attempt int) time.Duration
+}
+
+// New returns a Notifier with default HTTP client and backoff.
+func New() *Notifier {
+	return &Notifier{Client: &http.Client{}, Backoff: exponentialBackoff}
+}
+
+func exponentialBackoff(attempt int) time.Duration {
+	d := 500 * time.Millisecond << uint(attempt-1)
+	if d > 30*time.Second {
+		d = 30 * time.Second
+	}
+	return d
+}
+
+// Changed returns findings of current that are new or more severe than in previous.
+// With no previous scan every finding is considered new.
+func Changed(current, previous *v1.Report) []v1.Finding {
+	out := []v1.Finding{}
+	if current == nil {
+		return out
+	}
+	prev := map[string]string{}
+	if previous != nil {
+		for _, f := range previous.Findings {
+			prev[f.Fingerprint] = f.Severity
+		}
+	}
+	for _, f := range current.Findings {
+		old, seen := prev[f.Fingerprint]
+		if !seen || severityRank(f.Severity) > severityRank(old) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func severityRank(s string) int {
+	switch s {
+	case "critical":
+		return 2
+	case "warning":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Send posts the scan result to every configured webhook. It returns one error per failed webhook.
+func (n *Notifier) Send(ctx context.Context, hooks []config.WebhookConfig, current, previous *v1.Report) []error {
+	var errs []error
+	changed := Changed(current, previous)
+	for _, h := range hooks {
+		if h.OnlyNew && len(changed) == 0 {
+			continue
+		}
+		body, err := Render(h, current, previous, changed)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", hookName(h), err))
+			continue
+		}
+		if err := n.post(ctx, h, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", hookName(h), err))
+		}
+	}
+	return errs
+}
+
+func hookName(h config.WebhookConfig) string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.URL
+}
+
+// Render builds the request body for a webhook in its configured format.
+func Render(h config.WebhookConfig, current, previous *v1.Report, changed []v1.Finding) ([]byte, error) {
+	format := strings.ToLower(h.Format)
+	if format == "" || format == "generic" {
+		p := Payload{
+			Event:         "scan.completed",
+			SchemaVersion: current.SchemaVersion,
+			ScanID:        current.Scan.ScanID,
+			FinishedAt:    current.Scan.FinishedAt,
+			Host:          current.Target.Host.Hostname,
+			Summary:       current.Summary,
+			NewFindings:   changed,
+		}
+		if previous != nil {
+			p.PreviousScan = previous.Scan.ScanID
+		}
+		return json.Marshal(p)
+	}
+
+	text, err := renderText(h.Template, current, changed)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "slack":
+		return json.Marshal(map[string]interface{}{
+			"text": text,
+			"blocks": []map[string]interface{}{
+				{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": text}},
+			},
+		})
+	case "teams":
+		return json.Marshal(map[string]interface{}{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    fmt.Sprintf("docker-doctor scan %s", current.Scan.ScanID),
+			"themeColor": themeColor(current),
+			"title":      fmt.Sprintf("docker-doctor: %s", current.Target.Host.Hostname),
+			"text":       strings.ReplaceAll(text, "\n", "\n\n"),
+		})
+	}
+	return nil, fmt.Errorf("unsupported format %q", h.Format)
+}
+
+func renderText(tmpl string, current *v1.Report, changed []v1.Finding) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = defaultTemplate
+	}
+	t, err := template.New("webhook").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var buf strings.Builder
+	data := struct {
+		Report      *v1.Report
+		Host        string
+		NewFindings []v1.Finding
+	}{current, current.Target.Host.Hostname, changed}
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("template failed: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func themeColor(r *v1.Report) string {
+	switch {
+	case r.Summary.FindingCounts.Critical > 0:
+		return "FF3B5C"
+	case r.Summary.FindingCounts.Warning > 0:
+		return "FFB020"
+	default:
+		return "36D399"
+	}
+}
+
+// Sign returns the signature header value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (n *Notifier) post(ctx context.Context, h config.WebhookConfig, body []byte) error {
+	secret := h.Secret
+	if h.SecretEnv != "" {
+		// A receiver that verifies signatures would reject an unsigned request, so a
+		// missing secret is a configuration error rather than a reason to skip signing.
+		secret = os.Getenv(h.SecretEnv)
+		if secret == "" {
+			return fmt.Errorf("secret_env %s is empty or unset, not sending unsigned", h.SecretEnv)
+		}
+	}
+	timeout := time.Duration(h.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	backoff := n.Backoff
+	if backoff == nil {
+		backoff = exponentialBackoff
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= h.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff(attempt)):
+			}
+		}
+
+		retry, err := n.postOnce(ctx, h.URL, secret, body, timeout)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+// postOnce performs a single delivery attempt and reports whether a failure is retryable.
+func (n *Notifier) postOnce(ctx context.Context, url, secret string, body []byte, timeout time.Duration) (bool, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "docker-doctor")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected response status %s", resp.Status)
+}
+
+// LoadPrevious returns the most recent scan in outputDir (written as <outputDir>/<scanId>/scan.json)
+// for the same host that finished before current. It returns nil when none exists.
+func LoadPrevious(outputDir string, current *v1.Report) (*v1.Report, error) {
+	paths, err := filepath.Glob(filepath.Join(outputDir, "*", "scan.json"))
+	if err != nil {
+		return nil, err
+	}
+	var best *v1.Report
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		var r v1.Report
+		if err := json.Unmarshal(data, &r); err != nil || r.SchemaVersion == "" {
+			continue
+		}
+		if r.Scan.ScanID == current.Scan.ScanID || r.Target.Host.Hostname != current.Target.Host.Hostname {
+			continue
+		}
+		if !r.Scan.FinishedAt.Before(current.Scan.FinishedAt) {
+			continue
+		}
+		if best == nil || r.Scan.FinishedAt.After(best.Scan.FinishedAt) {
+			rr := r
+			best = &rr
+		}
+	}
+	return best, nil
+}
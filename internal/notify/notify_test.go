@@ -0,0 +1,185 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+)
+
+func testReports() (current, previous *v1.Report) {
+	previous = &v1.Report{
+		SchemaVersion: "1.0",
+		Scan:          v1.Scan{ScanID: "prev", FinishedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		Target:        v1.Target{Host: v1.TargetHost{Hostname: "host-a"}},
+		Findings: []v1.Finding{
+			{ID: "LOG_BLOAT", Fingerprint: "LOG_BLOAT:container=a", Severity: "warning"},
+			{ID: "VOLUME_BLOAT", Fingerprint: "VOLUME_BLOAT:volumes_unused", Severity: "info"},
+		},
+	}
+	current = &v1.Report{
+		SchemaVersion: "1.0",
+		Scan:          v1.Scan{ScanID: "cur", FinishedAt: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)},
+		Target:        v1.Target{Host: v1.TargetHost{Hostname: "host-a"}},
+		Summary:       v1.Summary{FindingCounts: v1.SummaryFindingCounts{Critical: 2, Info: 1}},
+		Findings: []v1.Finding{
+			{ID: "LOG_BLOAT", Fingerprint: "LOG_BLOAT:container=a", Severity: "critical", Summary: "logs grew"},
+			{ID: "OOM_KILLED", Fingerprint: "OOM_KILLED:container=b", Severity: "critical", Summary: "oom"},
+			{ID: "VOLUME_BLOAT", Fingerprint: "VOLUME_BLOAT:volumes_unused", Severity: "info"},
+		},
+	}
+	return current, previous
+}
+
+func TestChanged_NewAndWorsened(t *testing.T) {
+	current, previous := testReports()
+	changed := Changed(current, previous)
+	if len(changed) != 2 {
+		t.Fatalf("expected 2 new/worsened findings, got %+v", changed)
+	}
+	if changed[0].ID != "LOG_BLOAT" || changed[1].ID != "OOM_KILLED" {
+		t.Fatalf("unexpected changed findings: %+v", changed)
+	}
+	if n := len(Changed(current, nil)); n != 3 {
+		t.Fatalf("expected all findings without previous scan, got %d", n)
+	}
+}
+
+func TestSend_RetriesAndSigns(t *testing.T) {
+	current, previous := testReports()
+
+	var mu sync.Mutex
+	attempts := 0
+	var gotBody []byte
+	var gotSig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSig = r.Header.Get(SignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := &Notifier{Client: srv.Client(), Backoff: func(int) time.Duration { return time.Millisecond }}
+	hooks := []config.WebhookConfig{{Name: "generic", URL: srv.URL, Secret: "s3cret", Retries: 2}}
+	if errs := n.Send(context.Background(), hooks, current, previous); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+	if gotSig != Sign("s3cret", gotBody) {
+		t.Fatalf("signature mismatch: %q", gotSig)
+	}
+	var p Payload
+	if err := json.Unmarshal(gotBody, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.ScanID != "cur" || p.PreviousScan != "prev" || len(p.NewFindings) != 2 {
+		t.Fatalf("unexpected payload: %+v", p)
+	}
+}
+
+func TestSend_MissingSecretEnvIsAnError(t *testing.T) {
+	current, previous := testReports()
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	t.Setenv("DOCTOR_TEST_WEBHOOK_SECRET", "")
+	n := &Notifier{Client: srv.Client()}
+	hooks := []config.WebhookConfig{{Name: "signed", URL: srv.URL, SecretEnv: "DOCTOR_TEST_WEBHOOK_SECRET"}}
+	errs := n.Send(context.Background(), hooks, current, previous)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "DOCTOR_TEST_WEBHOOK_SECRET") {
+		t.Fatalf("expected an error naming the empty secret_env, got %v", errs)
+	}
+	if attempts != 0 {
+		t.Fatalf("expected no unsigned request, got %d", attempts)
+	}
+}
+
+func TestSend_OnlyNewSkipsUnchanged(t *testing.T) {
+	current, _ := testReports()
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	n := &Notifier{Client: srv.Client()}
+	hooks := []config.WebhookConfig{{URL: srv.URL, OnlyNew: true}}
+	if errs := n.Send(context.Background(), hooks, current, current); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if called {
+		t.Fatalf("expected no delivery when nothing changed")
+	}
+}
+
+func TestRender_SlackAndTeams(t *testing.T) {
+	current, previous := testReports()
+	changed := Changed(current, previous)
+
+	slack, err := Render(config.WebhookConfig{Format: "slack"}, current, previous, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(slack), "[critical] OOM_KILLED: oom") || !strings.Contains(string(slack), `"blocks"`) {
+		t.Fatalf("unexpected slack payload: %s", slack)
+	}
+
+	teams, err := Render(config.WebhookConfig{Format: "teams", Template: "{{len .NewFindings}} changed on {{.Host}}"}, current, previous, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(teams), `"@type":"MessageCard"`) || !strings.Contains(string(teams), "2 changed on host-a") {
+		t.Fatalf("unexpected teams payload: %s", teams)
+	}
+}
+
+func TestLoadPrevious_SameHostMostRecent(t *testing.T) {
+	current, previous := testReports()
+	dir := t.TempDir()
+	write := func(r *v1.Report) {
+		b, _ := json.Marshal(r)
+		if err := os.MkdirAll(filepath.Join(dir, r.Scan.ScanID), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, r.Scan.ScanID, "scan.json"), b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := *previous
+	other.Scan.ScanID = "other-host"
+	other.Target.Host.Hostname = "host-b"
+	write(previous)
+	write(&other)
+	write(current)
+
+	got, err := LoadPrevious(dir, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Scan.ScanID != "prev" {
+		t.Fatalf("expected previous scan 'prev', got %+v", got)
+	}
+}
@@ -0,0 +1,282 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dashu-baba/docker-doctor/internal/facts"
+)
+
+// Operation names, in the order they are applied. Earlier operations can make more
+// space reclaimable for later ones (e.g. removing stopped containers frees their images).
+const (
+	ContainerPrune = "container-prune"
+	ImagePrune     = "image-prune"
+	ImagePruneAll  = "image-prune-all"
+	BuilderPrune   = "builder-prune"
+	VolumePrune    = "volume-prune"
+	VolumePruneAll = "volume-prune-all"
+)
+
+// Operations lists every supported operation in execution order.
+var Operations = []string{ContainerPrune, ImagePrune, ImagePruneAll, BuilderPrune, VolumePrune, VolumePruneAll}
+
+// DefaultOperations is the plan used when none is chosen: nothing that deletes data
+// that cannot be rebuilt or re-pulled.
+var DefaultOperations = []string{ContainerPrune, ImagePrune, BuilderPrune}
+
+// Step is the simulated effect of one cleanup operation.
+type Step struct {
+	Name             string `json:"name"`
+	Command          string `json:"command"`
+	Risk             string `json:"risk"` // safe | planned | risky
+	Description      string `json:"description"`
+	Objects          int    `json:"objects"`
+	ReclaimableBytes uint64 `json:"reclaimableBytes"`
+}
+
+// Plan is an ordered set of cleanup steps.
+type Plan struct {
+	Steps                 []Step `json:"steps"`
+	TotalReclaimableBytes uint64 `json:"totalReclaimableBytes"`
+}
+
+// ParseOperations splits a comma-separated list of operation names and returns them in
+// execution order. An empty list yields DefaultOperations.
+func ParseOperations(s string) ([]string, error) {
+	want := map[string]bool{}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !known(name) {
+			return nil, fmt.Errorf("unknown operation %q (use %s)", name, strings.Join(Operations, ","))
+		}
+		want[name] = true
+	}
+	if len(want) == 0 {
+		return append([]string(nil), DefaultOperations...), nil
+	}
+	var out []string
+	for _, name := range Operations {
+		if want[name] {
+			out = append(out, name)
+		}
+	}
+	return out, nil
+}
+
+func known(name string) bool {
+	for _, op := range Operations {
+		if op == name {
+			return true
+		}
+	}
+	return false
+}
+
+// state is the mutable view of `/system/df` the simulation works on.
+type state struct {
+	images     []facts.DfImage
+	containers []facts.DfContainer
+	volumes    []facts.DfVolume
+	cache      []facts.DfBuildCache
+}
+
+// Simulate applies ops (in execution order) to a copy of df and returns the bytes each
+// would free given the previous ones ran. until is the builder prune age cutoff.
+//
+// Image sizes count only the image's unique layers (Size - SharedSize), so image
+// figures are a lower bound: shared layers are freed only once no remaining image uses them.
+func Simulate(df *facts.DockerSystemDfSummary, ops []string, until time.Duration, now time.Time) *Plan {
+	st := &state{
+		images:     append([]facts.DfImage(nil), df.Images...),
+		containers: append([]facts.DfContainer(nil), df.Containers...),
+		volumes:    append([]facts.DfVolume(nil), df.Volumes...),
+		cache:      append([]facts.DfBuildCache(nil), df.BuildCache...),
+	}
+
+	p := &Plan{Steps: []Step{}}
+	for _, op := range ops {
+		var s Step
+		switch op {
+		case ContainerPrune:
+			s = st.pruneContainers()
+		case ImagePrune:
+			s = st.pruneImages(false)
+		case ImagePruneAll:
+			s = st.pruneImages(true)
+		case BuilderPrune:
+			s = st.pruneBuildCache(until, now)
+		case VolumePrune:
+			s = st.pruneVolumes(false)
+		case VolumePruneAll:
+			s = st.pruneVolumes(true)
+		default:
+			continue
+		}
+		p.Steps = append(p.Steps, s)
+		p.TotalReclaimableBytes += s.ReclaimableBytes
+	}
+	return p
+}
+
+func (st *state) pruneContainers() Step {
+	s := Step{
+		Name:        ContainerPrune,
+		Command:     "docker container prune -f",
+		Risk:        "planned",
+		Description: "Remove stopped containers and their writable layers",
+	}
+	var kept []facts.DfContainer
+	for _, c := range st.containers {
+		switch c.State {
+		case "exited", "created", "dead":
+		default:
+			kept = append(kept, c)
+			continue
+		}
+		s.Objects++
+		s.ReclaimableBytes += positive(c.SizeRw)
+		for i := range st.images {
+			if st.images[i].ID == c.ImageID && st.images[i].Containers > 0 {
+				st.images[i].Containers--
+			}
+		}
+		for _, name := range c.Volumes {
+			for i := range st.volumes {
+				if st.volumes[i].Name == name && st.volumes[i].RefCount > 0 {
+					st.volumes[i].RefCount--
+				}
+			}
+		}
+	}
+	st.containers = kept
+	return s
+}
+
+func (st *state) pruneImages(all bool) Step {
+	s := Step{
+		Name:        ImagePrune,
+		Command:     "docker image prune -f",
+		Risk:        "safe",
+		Description: "Remove dangling (untagged) images not used by any container",
+	}
+	if all {
+		s.Name = ImagePruneAll
+		s.Command = "docker image prune -a -f"
+		s.Risk = "planned"
+		s.Description = "Remove all images not used by any container (they must be pulled again)"
+	}
+	var kept []facts.DfImage
+	for _, im := range st.images {
+		if im.Containers != 0 || (!all && !Dangling(im)) {
+			kept = append(kept, im)
+			continue
+		}
+		s.Objects++
+		s.ReclaimableBytes += UniqueSize(im)
+	}
+	st.images = kept
+	return s
+}
+
+func (st *state) pruneBuildCache(until time.Duration, now time.Time) Step {
+	s := Step{
+		Name:        BuilderPrune,
+		Command:     "docker builder prune -f",
+		Risk:        "safe",
+		Description: "Remove build cache records not in use",
+	}
+	cutoff := now
+	if until > 0 {
+		s.Command += " --filter until=" + until.String()
+		s.Description += fmt.Sprintf(" and unused for %s", until)
+		cutoff = now.Add(-until)
+	}
+	var kept []facts.DfBuildCache
+	for _, b := range st.cache {
+		last := b.CreatedAt
+		if b.LastUsedAt != nil {
+			last = *b.LastUsedAt
+		}
+		if b.InUse || (until > 0 && last.After(cutoff)) || b.KeptWithoutAll() {
+			kept = append(kept, b)
+			continue
+		}
+		s.Objects++
+		s.ReclaimableBytes += positive(b.Size)
+	}
+	st.cache = kept
+	return s
+}
+
+func (st *state) pruneVolumes(all bool) Step {
+	s := Step{
+		Name:        VolumePrune,
+		Command:     "docker volume prune -f",
+		Risk:        "risky",
+		Description: "Remove anonymous volumes not used by any container (data is lost)",
+	}
+	if all {
+		s.Name = VolumePruneAll
+		s.Command = "docker volume prune -a -f"
+		s.Description = "Remove all volumes not used by any container, including named ones (data is lost)"
+	}
+	var kept []facts.DfVolume
+	for _, v := range st.volumes {
+		if v.RefCount != 0 || (!all && !v.Anonymous) {
+			kept = append(kept, v)
+			continue
+		}
+		s.Objects++
+		s.ReclaimableBytes += positive(v.Size)
+	}
+	st.volumes = kept
+	return s
+}
+
+// Dangling reports whether an image has no tags.
+func Dangling(im facts.DfImage) bool {
+	for _, t := range im.RepoTags {
+		if t != "<none>:<none>" {
+			return false
+		}
+	}
+	return true
+}
+
+// UniqueSize returns the bytes held only by this image.
+func UniqueSize(im facts.DfImage) uint64 {
+	if im.SharedSize > 0 && im.SharedSize <= im.Size {
+		return uint64(im.Size - im.SharedSize)
+	}
+	return positive(im.Size)
+}
+
+func positive(v int64) uint64 {
+	if v < 0 {
+		return 0
+	}
+	return uint64(v)
+}
+
+// Script renders the plan as an ordered POSIX shell script.
+func (p *Plan) Script() string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Cleanup plan generated by docker-doctor. Review before running.\n")
+	fmt.Fprintf(&b, "# Estimated reclaimable space: %d bytes\n", p.TotalReclaimableBytes)
+	b.WriteString("set -eu\n")
+	for _, s := range p.Steps {
+		fmt.Fprintf(&b, "\n# %s (risk: %s, %d object(s), ~%d bytes)\n", s.Description, s.Risk, s.Objects, s.ReclaimableBytes)
+		if s.Objects == 0 {
+			b.WriteString("# nothing to remove; skipped\n")
+			b.WriteString("# ")
+		}
+		b.WriteString(s.Command + "\n")
+	}
+	return b.String()
+}
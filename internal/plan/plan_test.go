@@ -0,0 +1,112 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dashu-baba/docker-doctor/internal/facts"
+)
+
+func testDf(now time.Time) *facts.DockerSystemDfSummary {
+	old := now.Add(-30 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+	return &facts.DockerSystemDfSummary{
+		Images: []facts.DfImage{
+			{ID: "dangling", RepoTags: []string{"<none>:<none>"}, Size: 100, SharedSize: 0, Containers: 0},
+			{ID: "app-old", RepoTags: []string{"app:1"}, Size: 1000, SharedSize: 400, Containers: 1},
+			{ID: "app-new", RepoTags: []string{"app:2"}, Size: 1000, SharedSize: 400, Containers: 1},
+		},
+		Containers: []facts.DfContainer{
+			{ID: "c-old", ImageID: "app-old", State: "exited", SizeRw: 50, Volumes: []string{"anon1"}},
+			{ID: "c-new", ImageID: "app-new", State: "running", SizeRw: 10, Volumes: []string{"data"}},
+		},
+		Volumes: []facts.DfVolume{
+			{Name: "anon1", Size: 300, RefCount: 1, Anonymous: true},
+			{Name: "named", Size: 700, RefCount: 0},
+			{Name: "data", Size: 900, RefCount: 1},
+		},
+		BuildCache: []facts.DfBuildCache{
+			{ID: "b1", Size: 200, LastUsedAt: &old},
+			{ID: "b2", Size: 200, LastUsedAt: &recent},
+			{ID: "b3", Size: 200, InUse: true, LastUsedAt: &old},
+			{ID: "b4", Size: 200, Shared: true, LastUsedAt: &old},
+		},
+	}
+}
+
+func TestSimulate_Standalone(t *testing.T) {
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	df := testDf(now)
+	want := map[string][2]uint64{ // objects, bytes
+		ContainerPrune: {1, 50},
+		ImagePrune:     {1, 100},
+		ImagePruneAll:  {1, 100}, // app images are still referenced by containers
+		BuilderPrune:   {1, 200}, // b1; b4 is shared and kept without --all
+		VolumePrune:    {0, 0},   // anon1 is still referenced by c-old
+		VolumePruneAll: {1, 700},
+	}
+	for op, w := range want {
+		p := Simulate(df, []string{op}, 168*time.Hour, now)
+		s := p.Steps[0]
+		if uint64(s.Objects) != w[0] || s.ReclaimableBytes != w[1] {
+			t.Fatalf("%s: got %d objects / %d bytes, want %v", op, s.Objects, s.ReclaimableBytes, w)
+		}
+	}
+	if df.Images[1].Containers != 1 || df.Volumes[0].RefCount != 1 {
+		t.Fatalf("Simulate must not modify its input")
+	}
+}
+
+func TestSimulate_Cumulative(t *testing.T) {
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	p := Simulate(testDf(now), Operations, 0, now)
+	// container prune frees app-old (600 unique) for image-prune-all and anon1 for volume-prune.
+	got := map[string]uint64{}
+	for _, s := range p.Steps {
+		got[s.Name] = s.ReclaimableBytes
+	}
+	if got[ImagePruneAll] != 600 || got[VolumePrune] != 300 || got[VolumePruneAll] != 700 || got[BuilderPrune] != 400 {
+		t.Fatalf("unexpected cumulative plan: %v", got)
+	}
+	if p.TotalReclaimableBytes != 50+100+600+400+300+700 {
+		t.Fatalf("unexpected total %d", p.TotalReclaimableBytes)
+	}
+
+	script := p.Script()
+	if !strings.HasPrefix(script, "#!/bin/sh\n") || strings.Index(script, "docker container prune") > strings.Index(script, "docker image prune") {
+		t.Fatalf("script not ordered:\n%s", script)
+	}
+}
+
+func TestSimulate_BuilderPruneKeepsWhatDockerKeeps(t *testing.T) {
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-30 * 24 * time.Hour)
+	df := &facts.DockerSystemDfSummary{BuildCache: []facts.DfBuildCache{
+		{ID: "layer", Type: "regular", Size: 100, LastUsedAt: &old},
+		{ID: "mount", Type: "exec.cachemount", Size: 200, LastUsedAt: &old},
+		{ID: "dockerfile", Type: "frontend", Size: 400, LastUsedAt: &old},
+		{ID: "context", Type: "internal", Size: 800, LastUsedAt: &old},
+		{ID: "base", Type: "regular", Size: 1600, Shared: true, LastUsedAt: &old},
+	}}
+	s := Simulate(df, []string{BuilderPrune}, 0, now).Steps[0]
+	if strings.Contains(s.Command, "--all") {
+		t.Fatalf("unexpected --all in %q", s.Command)
+	}
+	if s.Objects != 2 || s.ReclaimableBytes != 300 {
+		t.Fatalf("expected only the regular and cache mount records, got %d objects / %d bytes", s.Objects, s.ReclaimableBytes)
+	}
+}
+
+func TestParseOperations(t *testing.T) {
+	ops, err := ParseOperations("volume-prune, container-prune")
+	if err != nil || strings.Join(ops, ",") != "container-prune,volume-prune" {
+		t.Fatalf("got %v, %v", ops, err)
+	}
+	if ops, _ := ParseOperations(""); len(ops) != len(DefaultOperations) {
+		t.Fatalf("expected defaults, got %v", ops)
+	}
+	if _, err := ParseOperations("system-prune"); err == nil {
+		t.Fatalf("expected error for unknown operation")
+	}
+}
@@ -0,0 +1,344 @@
+package remediate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+)
+
+// Docker is the subset of the Docker API client used to execute actions.
+// *client.Client satisfies it; tests use a fake.
+type Docker interface {
+	ImageRemove(ctx context.Context, imageID string, options dtypes.ImageRemoveOptions) ([]dtypes.ImageDeleteResponseItem, error)
+	ImageInspectWithRaw(ctx context.Context, imageID string) (dtypes.ImageInspect, []byte, error)
+	BuildCachePrune(ctx context.Context, opts dtypes.BuildCachePruneOptions) (*dtypes.BuildCachePruneReport, error)
+	DiskUsage(ctx context.Context, opts dtypes.DiskUsageOptions) (dtypes.DiskUsage, error)
+	VolumeRemove(ctx context.Context, volumeID string, force bool) error
+	VolumeInspect(ctx context.Context, volumeID string) (volume.Volume, error)
+	ContainerInspect(ctx context.Context, containerID string) (dtypes.ContainerJSON, error)
+}
+
+// Step is one action selected from a scan, with the finding it came from.
+type Step struct {
+	FindingID   string    `json:"findingId"`
+	Fingerprint string    `json:"fingerprint"`
+	Risk        string    `json:"risk"`
+	Action      v1.Action `json:"action"`
+}
+
+// Describe returns the docker CLI equivalent of the step, for prompts and output.
+func (s Step) Describe() string {
+	return v1.ActionCommand(s.Action)
+}
+
+// Result is the outcome of a step; it is also the audit log record.
+type Result struct {
+	Time    time.Time `json:"time"`
+	Step    Step      `json:"step"`
+	Status  string    `json:"status"` // dry_run | applied | failed | declined
+	Error   string    `json:"error,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+	Recheck string    `json:"recheck,omitempty"`
+}
+
+// RiskRank orders recommendation risk levels; unknown levels rank as risky.
+func RiskRank(risk string) int {
+	switch strings.ToLower(strings.TrimSpace(risk)) {
+	case "safe":
+		return 0
+	case "planned":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ValidRisk reports whether risk is a known recommendation risk level.
+func ValidRisk(risk string) bool {
+	switch risk {
+	case "safe", "planned", "risky":
+		return true
+	}
+	return false
+}
+
+// Plan collects the typed actions of a scan, split into steps allowed by the
+// risk gate and steps held back because their risk exceeds allow.
+func Plan(r *v1.Report, allow string) (allowed, held []Step) {
+	seen := map[string]bool{}
+	for _, f := range r.Findings {
+		for _, reco := range f.Recommendations {
+			for _, a := range reco.Actions {
+				key := a.Type + "|" + a.Target + "|" + a.Params["until"]
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				st := Step{FindingID: f.ID, Fingerprint: f.Fingerprint, Risk: reco.Risk, Action: a}
+				if RiskRank(reco.Risk) <= RiskRank(allow) {
+					allowed = append(allowed, st)
+				} else {
+					held = append(held, st)
+				}
+			}
+		}
+	}
+	return allowed, held
+}
+
+// Executor runs steps against the Docker API.
+type Executor struct {
+	Docker Docker
+	// Now is used for audit timestamps; defaults to time.Now.
+	Now func() time.Time
+}
+
+// Execute runs a single step. With dryRun nothing is changed and the result has status dry_run.
+// After an applied action, the effect is re-checked and recorded in Result.Recheck.
+func (e *Executor) Execute(ctx context.Context, st Step, dryRun bool) Result {
+	now := time.Now
+	if e.Now != nil {
+		now = e.Now
+	}
+	res := Result{Time: now().UTC(), Step: st}
+	if dryRun {
+		res.Status = "dry_run"
+		res.Detail = st.Describe()
+		return res
+	}
+
+	// Resolve the image ID before removal: the target may be one of several tags,
+	// and removing it only untags the image.
+	var imageID string
+	if st.Action.Type == "image_remove" {
+		if img, _, err := e.Docker.ImageInspectWithRaw(ctx, st.Action.Target); err == nil {
+			imageID = img.ID
+		}
+	}
+
+	detail, err := e.apply(ctx, st.Action)
+	res.Detail = detail
+	if err != nil {
+		res.Status = "failed"
+		res.Error = err.Error()
+		return res
+	}
+	res.Status = "applied"
+	res.Recheck = e.recheck(ctx, st.Action, res.Time, imageID)
+	return res
+}
+
+func (e *Executor) apply(ctx context.Context, a v1.Action) (string, error) {
+	switch a.Type {
+	case "image_remove":
+		// No force: the daemon refuses to remove images still used by a container.
+		items, err := e.Docker.ImageRemove(ctx, a.Target, dtypes.ImageRemoveOptions{PruneChildren: true})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d layer(s)/tag(s) removed", len(items)), nil
+	case "builder_prune":
+		opts := dtypes.BuildCachePruneOptions{Filters: filters.NewArgs()}
+		if until := a.Params["until"]; until != "" {
+			opts.Filters.Add("until", until)
+		}
+		rep, err := e.Docker.BuildCachePrune(ctx, opts)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d cache record(s) removed, %d bytes reclaimed", len(rep.CachesDeleted), rep.SpaceReclaimed), nil
+	case "volume_remove":
+		// No force: the daemon refuses to remove volumes in use.
+		if err := e.Docker.VolumeRemove(ctx, a.Target, false); err != nil {
+			return "", err
+		}
+		return "volume removed", nil
+	case "log_truncate":
+		return e.truncateLog(ctx, a)
+	}
+	return "", fmt.Errorf("unsupported action type %q", a.Type)
+}
+
+// truncateLog empties a json-file log in place. The daemon keeps the file open in
+// append mode, so truncation (not rename) is the only safe way to shrink it.
+// mode=rotate first copies the current content to <log>.<timestamp> (see rotateLog).
+func (e *Executor) truncateLog(ctx context.Context, a v1.Action) (string, error) {
+	c, err := e.Docker.ContainerInspect(ctx, a.Target)
+	if err != nil {
+		return "", err
+	}
+	if c.HostConfig != nil && c.HostConfig.LogConfig.Type != "" && c.HostConfig.LogConfig.Type != "json-file" {
+		return "", fmt.Errorf("log driver %q is not json-file", c.HostConfig.LogConfig.Type)
+	}
+	if c.LogPath == "" {
+		return "", fmt.Errorf("container has no log path")
+	}
+	st, err := os.Stat(c.LogPath)
+	if err != nil {
+		return "", fmt.Errorf("log file not accessible (host FS required): %w", err)
+	}
+
+	if a.Params["mode"] == "rotate" {
+		dst := c.LogPath + "." + time.Now().UTC().Format("20060102T150405Z")
+		n, err := rotateLog(c.LogPath, dst)
+		if err != nil {
+			return "", fmt.Errorf("failed to rotate log: %w", err)
+		}
+		return fmt.Sprintf("rotated %s to %s (%d bytes)", c.LogPath, dst, n), nil
+	}
+	if err := os.Truncate(c.LogPath, 0); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("truncated %s (%d bytes)", c.LogPath, st.Size()), nil
+}
+
+// rotateLog copies src to dst, then empties src and returns the number of bytes copied.
+// A file cannot be truncated from the front, so lines the daemon appends during the
+// copy are picked up by a second read right before the truncate. Only lines written
+// between that read and the truncate itself are lost, the same window as logrotate's
+// copytruncate.
+func rotateLog(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return n, err
+	}
+	// in is still positioned at the end of the first copy.
+	tail, err := io.Copy(out, in)
+	n += tail
+	if err != nil {
+		out.Close()
+		return n, err
+	}
+	if err := os.Truncate(src, 0); err != nil {
+		out.Close()
+		return n, err
+	}
+	return n, out.Close()
+}
+
+// recheck verifies the effect of an applied action. imageID is the ID the
+// image_remove target resolved to before removal, if known.
+func (e *Executor) recheck(ctx context.Context, a v1.Action, now time.Time, imageID string) string {
+	switch a.Type {
+	case "image_remove":
+		if imageID == "" {
+			imageID = a.Target
+		}
+		img, _, err := e.Docker.ImageInspectWithRaw(ctx, imageID)
+		if client.IsErrNotFound(err) {
+			return "ok: image no longer exists"
+		} else if err != nil {
+			return "unknown: " + err.Error()
+		}
+		if len(img.RepoTags) > 0 {
+			return fmt.Sprintf("failed: image %s still exists, still tagged %s", imageID, strings.Join(img.RepoTags, ", "))
+		}
+		return "failed: image still exists"
+	case "volume_remove":
+		if _, err := e.Docker.VolumeInspect(ctx, a.Target); client.IsErrNotFound(err) {
+			return "ok: volume no longer exists"
+		} else if err != nil {
+			return "unknown: " + err.Error()
+		}
+		return "failed: volume still exists"
+	case "log_truncate":
+		c, err := e.Docker.ContainerInspect(ctx, a.Target)
+		if err != nil {
+			return "unknown: " + err.Error()
+		}
+		st, err := os.Stat(c.LogPath)
+		if err != nil {
+			return "unknown: " + err.Error()
+		}
+		return fmt.Sprintf("ok: log is now %d bytes", st.Size())
+	case "builder_prune":
+		return e.recheckBuildCache(ctx, a.Params["until"], now)
+	}
+	return ""
+}
+
+// recheckBuildCache reports the build cache a prune with the same cutoff would still
+// remove. Records `docker builder prune` keeps without --all (in use, shared,
+// internal and frontend) do not count.
+func (e *Executor) recheckBuildCache(ctx context.Context, until string, now time.Time) string {
+	cutoff := now
+	if until != "" {
+		d, err := time.ParseDuration(until)
+		if err != nil {
+			return fmt.Sprintf("unknown: cannot check until=%s: %v", until, err)
+		}
+		cutoff = now.Add(-d)
+	}
+	du, err := e.Docker.DiskUsage(ctx, dtypes.DiskUsageOptions{Types: []dtypes.DiskUsageObject{dtypes.BuildCacheObject}})
+	if err != nil {
+		return "unknown: " + err.Error()
+	}
+	var total, left int64
+	records := 0
+	for _, b := range du.BuildCache {
+		total += b.Size
+		last := b.CreatedAt
+		if b.LastUsedAt != nil {
+			last = *b.LastUsedAt
+		}
+		if b.InUse || b.Shared || b.Type == "internal" || b.Type == "frontend" || last.After(cutoff) {
+			continue
+		}
+		records++
+		left += b.Size
+	}
+	if records > 0 {
+		return fmt.Sprintf("failed: %d prunable cache record(s) (%d bytes) remain, build cache is %d bytes", records, left, total)
+	}
+	return fmt.Sprintf("ok: no prunable cache records remain, build cache is %d bytes", total)
+}
+
+// AuditLog appends results as JSON lines.
+type AuditLog struct {
+	w io.Writer
+}
+
+// OpenAuditLog opens (or creates) an append-only audit log file.
+func OpenAuditLog(path string) (*AuditLog, io.Closer, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &AuditLog{w: f}, f, nil
+}
+
+// NewAuditLog wraps w as an audit log.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// Record writes one result.
+func (l *AuditLog) Record(res Result) error {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	_, err = l.w.Write(append(b, '\n'))
+	return err
+}
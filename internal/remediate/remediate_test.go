@@ -0,0 +1,249 @@
+package remediate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+
+	v1 "github.com/dashu-baba/docker-doctor/internal/schema/v1"
+)
+
+type fakeDocker struct {
+	images  map[string]bool
+	tags    map[string]string // tag -> image ID
+	volumes map[string]bool
+	logPath string
+	pruned  []string
+	cache   []*dtypes.BuildCache
+}
+
+// ImageRemove mimics the daemon: removing a tag only untags the image unless it was the last one.
+func (f *fakeDocker) ImageRemove(ctx context.Context, ref string, _ dtypes.ImageRemoveOptions) ([]dtypes.ImageDeleteResponseItem, error) {
+	if id, ok := f.tags[ref]; ok {
+		delete(f.tags, ref)
+		if len(f.tagsOf(id)) > 0 {
+			return []dtypes.ImageDeleteResponseItem{{Untagged: ref}}, nil
+		}
+		ref = id
+	}
+	if !f.images[ref] {
+		return nil, errdefs.NotFound(errors.New("no such image"))
+	}
+	delete(f.images, ref)
+	return []dtypes.ImageDeleteResponseItem{{Deleted: ref}}, nil
+}
+
+func (f *fakeDocker) ImageInspectWithRaw(ctx context.Context, ref string) (dtypes.ImageInspect, []byte, error) {
+	if id, ok := f.tags[ref]; ok {
+		ref = id
+	}
+	if !f.images[ref] {
+		return dtypes.ImageInspect{}, nil, errdefs.NotFound(errors.New("no such image"))
+	}
+	return dtypes.ImageInspect{ID: ref, RepoTags: f.tagsOf(ref)}, nil, nil
+}
+
+func (f *fakeDocker) tagsOf(id string) []string {
+	var tags []string
+	for tag, tid := range f.tags {
+		if tid == id {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func (f *fakeDocker) BuildCachePrune(ctx context.Context, opts dtypes.BuildCachePruneOptions) (*dtypes.BuildCachePruneReport, error) {
+	f.pruned = append(f.pruned, opts.Filters.Get("until")...)
+	return &dtypes.BuildCachePruneReport{CachesDeleted: []string{"a", "b"}, SpaceReclaimed: 2048}, nil
+}
+
+func (f *fakeDocker) DiskUsage(ctx context.Context, opts dtypes.DiskUsageOptions) (dtypes.DiskUsage, error) {
+	return dtypes.DiskUsage{BuildCache: f.cache}, nil
+}
+
+func (f *fakeDocker) VolumeRemove(ctx context.Context, id string, force bool) error {
+	if force {
+		return errors.New("force must not be used")
+	}
+	if !f.volumes[id] {
+		return errdefs.NotFound(errors.New("no such volume"))
+	}
+	delete(f.volumes, id)
+	return nil
+}
+
+func (f *fakeDocker) VolumeInspect(ctx context.Context, id string) (volume.Volume, error) {
+	if !f.volumes[id] {
+		return volume.Volume{}, errdefs.NotFound(errors.New("no such volume"))
+	}
+	return volume.Volume{Name: id}, nil
+}
+
+func (f *fakeDocker) ContainerInspect(ctx context.Context, id string) (dtypes.ContainerJSON, error) {
+	return dtypes.ContainerJSON{
+		ContainerJSONBase: &dtypes.ContainerJSONBase{
+			ID:         id,
+			LogPath:    f.logPath,
+			HostConfig: &container.HostConfig{LogConfig: container.LogConfig{Type: "json-file"}},
+		},
+	}, nil
+}
+
+func testReport() *v1.Report {
+	return &v1.Report{Findings: []v1.Finding{
+		{ID: "DOCKER_STORAGE_BLOAT", Recommendations: []v1.Recommendation{
+			{Risk: "safe", Title: "Prune"},
+			{Risk: "safe", Actions: []v1.Action{{Type: "builder_prune", Params: map[string]string{"until": "168h"}}}},
+		}},
+		{ID: "LOG_BLOAT", Recommendations: []v1.Recommendation{
+			{Risk: "planned", Actions: []v1.Action{{Type: "log_truncate", Target: "c1", Params: map[string]string{"mode": "rotate"}}}},
+		}},
+		{ID: "VOLUME_BLOAT", Recommendations: []v1.Recommendation{
+			{Risk: "risky", Actions: []v1.Action{{Type: "volume_remove", Target: "data"}}},
+		}},
+	}}
+}
+
+func TestPlan_RiskGate(t *testing.T) {
+	for allow, want := range map[string]int{"safe": 1, "planned": 2, "risky": 3} {
+		allowed, held := Plan(testReport(), allow)
+		if len(allowed) != want || len(allowed)+len(held) != 3 {
+			t.Fatalf("allow=%s: got %d allowed, %d held", allow, len(allowed), len(held))
+		}
+	}
+}
+
+func TestExecute_DryRunChangesNothing(t *testing.T) {
+	fd := &fakeDocker{volumes: map[string]bool{"data": true}}
+	ex := &Executor{Docker: fd}
+	allowed, _ := Plan(testReport(), "risky")
+	for _, st := range allowed {
+		res := ex.Execute(context.Background(), st, true)
+		if res.Status != "dry_run" || res.Detail == "" {
+			t.Fatalf("unexpected dry-run result: %+v", res)
+		}
+	}
+	if !fd.volumes["data"] || len(fd.pruned) != 0 {
+		t.Fatalf("dry-run must not change state")
+	}
+}
+
+func TestExecute_ApplyAndRecheck(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "c1-json.log")
+	if err := os.WriteFile(logPath, bytes.Repeat([]byte("x"), 1024), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	recent, old := now.Add(-time.Hour), now.Add(-30*24*time.Hour)
+	fd := &fakeDocker{images: map[string]bool{"sha256:old": true}, volumes: map[string]bool{"data": true}, logPath: logPath, cache: []*dtypes.BuildCache{
+		{ID: "recent", Type: "regular", Size: 100, LastUsedAt: &recent},
+		{ID: "base", Type: "regular", Size: 200, Shared: true, LastUsedAt: &old},
+	}}
+	ex := &Executor{Docker: fd, Now: func() time.Time { return now }}
+
+	allowed, _ := Plan(testReport(), "risky")
+	allowed = append(allowed, Step{FindingID: "IMAGE", Risk: "safe", Action: v1.Action{Type: "image_remove", Target: "sha256:old"}})
+	var audit bytes.Buffer
+	log := NewAuditLog(&audit)
+	for _, st := range allowed {
+		res := ex.Execute(context.Background(), st, false)
+		if res.Status != "applied" {
+			t.Fatalf("%s: expected applied, got %+v", st.Action.Type, res)
+		}
+		if res.Recheck[:3] != "ok:" {
+			t.Fatalf("%s: unexpected recheck %q", st.Action.Type, res.Recheck)
+		}
+		if err := log.Record(res); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(fd.pruned) != 1 || fd.pruned[0] != "168h" {
+		t.Fatalf("expected builder prune with until filter, got %v", fd.pruned)
+	}
+	if st, _ := os.Stat(logPath); st.Size() != 0 {
+		t.Fatalf("expected log truncated, size %d", st.Size())
+	}
+	rotated, _ := filepath.Glob(logPath + ".*")
+	if len(rotated) != 1 {
+		t.Fatalf("expected one rotated log copy, got %v", rotated)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(audit.Bytes()), []byte("\n"))
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 audit records, got %d", len(lines))
+	}
+	var rec Result
+	if err := json.Unmarshal(lines[0], &rec); err != nil || rec.Status != "applied" {
+		t.Fatalf("bad audit record %s: %v", lines[0], err)
+	}
+}
+
+func TestExecute_BuilderPruneRecheckReportsLeftovers(t *testing.T) {
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-30 * 24 * time.Hour)
+	fd := &fakeDocker{cache: []*dtypes.BuildCache{
+		{ID: "stale", Type: "regular", Size: 300, LastUsedAt: &old},
+		{ID: "dockerfile", Type: "frontend", Size: 50, LastUsedAt: &old},
+	}}
+	ex := &Executor{Docker: fd, Now: func() time.Time { return now }}
+	res := ex.Execute(context.Background(), Step{Action: v1.Action{Type: "builder_prune", Params: map[string]string{"until": "168h"}}}, false)
+	if res.Recheck != "failed: 1 prunable cache record(s) (300 bytes) remain, build cache is 350 bytes" {
+		t.Fatalf("unexpected recheck %q", res.Recheck)
+	}
+}
+
+func TestExecute_ImageRemoveRecheckFollowsImageID(t *testing.T) {
+	fd := &fakeDocker{
+		images: map[string]bool{"sha256:app": true},
+		tags:   map[string]string{"app:old": "sha256:app", "app:latest": "sha256:app"},
+	}
+	ex := &Executor{Docker: fd}
+	res := ex.Execute(context.Background(), Step{Action: v1.Action{Type: "image_remove", Target: "app:old"}}, false)
+	if res.Status != "applied" || res.Recheck != "failed: image sha256:app still exists, still tagged app:latest" {
+		t.Fatalf("expected recheck to see the image behind the removed tag, got %+v", res)
+	}
+
+	res = ex.Execute(context.Background(), Step{Action: v1.Action{Type: "image_remove", Target: "app:latest"}}, false)
+	if res.Status != "applied" || res.Recheck != "ok: image no longer exists" {
+		t.Fatalf("expected the last tag to remove the image, got %+v", res)
+	}
+}
+
+func TestRotateLog_CopiesAndEmpties(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "c1-json.log"), filepath.Join(dir, "c1-json.log.1")
+	if err := os.WriteFile(src, []byte("line1\nline2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	n, err := rotateLog(src, dst)
+	if err != nil || n != 12 {
+		t.Fatalf("expected 12 bytes rotated, got %d, %v", n, err)
+	}
+	if b, _ := os.ReadFile(dst); string(b) != "line1\nline2\n" {
+		t.Fatalf("unexpected rotated copy %q", b)
+	}
+	if st, _ := os.Stat(src); st.Size() != 0 {
+		t.Fatalf("expected source log emptied, size %d", st.Size())
+	}
+}
+
+func TestExecute_FailureIsReported(t *testing.T) {
+	ex := &Executor{Docker: &fakeDocker{}}
+	res := ex.Execute(context.Background(), Step{Action: v1.Action{Type: "volume_remove", Target: "missing"}}, false)
+	if res.Status != "failed" || res.Error == "" {
+		t.Fatalf("expected failure, got %+v", res)
+	}
+}
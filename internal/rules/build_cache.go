@@ -0,0 +1,174 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/facts"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// buildCacheAgeBuckets are upper bounds (exclusive) for last-used age buckets.
+var buildCacheAgeBuckets = []struct {
+	label string
+	max   time.Duration
+}{
+	{"lt_1d", 24 * time.Hour},
+	{"1d_7d", 7 * 24 * time.Hour},
+	{"7d_30d", 30 * 24 * time.Hour},
+	{"gt_30d", 0},
+}
+
+func checkBuildCache(report *types.Report, cfg *config.Config, df *facts.DockerSystemDfSummary) {
+	// BUILD_CACHE_BLOAT (needs per-record /system/df data)
+	if !cfg.Rules.BuildCache.Enabled || df == nil || len(df.BuildCache) == 0 {
+		return
+	}
+	checkBuildCacheAt(report, cfg, df, time.Now())
+}
+
+func checkBuildCacheAt(report *types.Report, cfg *config.Config, df *facts.DockerSystemDfSummary, now time.Time) {
+	rule := cfg.Rules.BuildCache
+	idleAfter := time.Duration(rule.IdleDays) * 24 * time.Hour
+
+	var total, shared, private, inUse, idle, reclaimable uint64
+	byType := map[string]uint64{}
+	byAge := map[string]uint64{}
+	idleRecords := 0
+	for _, b := range df.BuildCache {
+		size := uint64(max64(b.Size, 0))
+		total += size
+
+		typ := b.Type
+		if typ == "" {
+			typ = "unknown"
+		}
+		byType[typ] += size
+
+		if b.Shared {
+			shared += size
+		} else {
+			private += size
+		}
+
+		last := b.CreatedAt
+		if b.LastUsedAt != nil {
+			last = *b.LastUsedAt
+		}
+		age := now.Sub(last)
+		for _, bucket := range buildCacheAgeBuckets {
+			if bucket.max == 0 || age < bucket.max {
+				byAge[bucket.label] += size
+				break
+			}
+		}
+
+		if b.InUse {
+			inUse += size
+			continue
+		}
+		if age >= idleAfter {
+			idle += size
+			idleRecords++
+			// Counted like `docker-doctor plan` counts the same prune command.
+			if !b.KeptWithoutAll() {
+				reclaimable += size
+			}
+		}
+	}
+
+	if total <= rule.SizeThreshold {
+		return
+	}
+
+	severity := "medium"
+	if rule.SizeThreshold > 0 && total > rule.SizeThreshold*2 {
+		severity = "high"
+	}
+
+	pruneCmd := "docker builder prune -f"
+	until := ""
+	idleLabel := "not in use"
+	if idleAfter > 0 {
+		until = fmt.Sprintf("%dh", rule.IdleDays*24)
+		pruneCmd += " --filter until=" + until
+		idleLabel = fmt.Sprintf("unused for %d+ days", rule.IdleDays)
+	}
+
+	solutions := []string{
+		fmt.Sprintf("Prune build cache %s (frees about %s): '%s'", idleLabel, humanBytes(reclaimable), pruneCmd),
+		"Inspect individual cache records: 'docker buildx du --verbose'",
+		fmt.Sprintf("Build cache by type: %s", formatBreakdown(byType)),
+	}
+	if byType["exec.cachemount"] > total/2 {
+		solutions = append(solutions, "Consider bounding 'RUN --mount=type=cache' directories; cache mounts dominate the build cache.")
+	}
+	if byType["source.local"] > total/2 {
+		solutions = append(solutions, "Consider a tighter .dockerignore; local build contexts dominate the build cache.")
+	}
+	solutions = append(solutions, "Consider setting a builder GC policy ('builder.gc' in daemon.json) to cap the cache size automatically.")
+
+	var actions []types.Action
+	if idleRecords > 0 {
+		params := map[string]string{}
+		if until != "" {
+			params["until"] = until
+		}
+		actions = append(actions, types.Action{Type: "builder_prune", Risk: "safe", Params: params})
+	}
+
+	report.Issues = append(report.Issues, types.Issue{
+		RuleID:      "BUILD_CACHE_BLOAT",
+		Subject:     "build_cache",
+		Severity:    severity,
+		Category:    "storage_bloat",
+		Description: fmt.Sprintf("Build cache uses %s across %d records, exceeding threshold of %s; %s is %s", humanBytes(total), len(df.BuildCache), humanBytes(rule.SizeThreshold), humanBytes(idle), idleLabel),
+		Facts: map[string]interface{}{
+			"total_size":        total,
+			"size_threshold":    rule.SizeThreshold,
+			"records":           len(df.BuildCache),
+			"idle_days":         rule.IdleDays,
+			"idle_records":      idleRecords,
+			"idle_bytes":        idle,
+			"reclaimable_bytes": reclaimable,
+			"in_use_bytes":      inUse,
+			"shared_bytes":      shared,
+			"private_bytes":     private,
+			"bytes_by_type":     byType,
+			"bytes_by_age":      byAge,
+		},
+		Solutions: solutions,
+		Actions:   actions,
+	})
+}
+
+// formatBreakdown renders a size map as "a (1 GB), b (2 MB)" sorted by size descending.
+func formatBreakdown(m map[string]uint64) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] > m[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	out := ""
+	for i, k := range keys {
+		if i > 0 {
+			out += ", "
+		}
+		out += fmt.Sprintf("%s (%s)", k, humanBytes(m[k]))
+	}
+	return out
+}
+
+func max64(v, min int64) int64 {
+	if v < min {
+		return min
+	}
+	return v
+}
@@ -0,0 +1,173 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// cgroupController describes a controller whose absence makes a kind of limit ineffective.
+type cgroupController struct {
+	name     string // as listed in cgroup.controllers or /proc/cgroups
+	limit    string // docker run flag that stops working
+	severity string
+	solution string
+	// uses reports whether a container configured the limit.
+	uses func(l *types.ResourceLimits) bool
+	// supported reads the daemon's view from /info.
+	supported func(s *types.LimitSupport) bool
+	// daemonWarning is the DAEMON_WARNINGS class that already says everything this
+	// controller's finding would; the controller is skipped when it fired.
+	daemonWarning string
+}
+
+var cgroupControllers = []cgroupController{
+	{
+		name:      "memory",
+		limit:     "--memory",
+		severity:  "medium",
+		solution:  "Enable the memory controller: add 'cgroup_enable=memory' to the kernel command line (common on Raspberry Pi OS) and reboot.",
+		uses:      func(l *types.ResourceLimits) bool { return l.Memory > 0 },
+		supported: func(s *types.LimitSupport) bool { return s.Memory },
+	},
+	{
+		name:          "swap",
+		limit:         "--memory-swap",
+		severity:      "low",
+		solution:      "On cgroup v1 add 'swapaccount=1' to the kernel command line and reboot; cgroup v2 accounts swap natively.",
+		uses:          func(l *types.ResourceLimits) bool { return l.MemorySwap > 0 },
+		supported:     func(s *types.LimitSupport) bool { return s.Swap },
+		daemonWarning: "no_swap_limit",
+	},
+	{
+		name:      "pids",
+		limit:     "--pids-limit",
+		severity:  "medium",
+		solution:  "Enable the pids controller (kernel CONFIG_CGROUP_PIDS) or move to cgroup v2.",
+		uses:      func(l *types.ResourceLimits) bool { return l.PidsLimit > 0 },
+		supported: func(s *types.LimitSupport) bool { return s.Pids },
+	},
+	{
+		name:      "cpu",
+		limit:     "--cpus",
+		severity:  "low",
+		solution:  "Use a kernel built with CONFIG_CFS_BANDWIDTH and make sure the cpu controller is delegated to Docker.",
+		uses:      func(l *types.ResourceLimits) bool { return cpuLimitCores(l) > 0 },
+		supported: func(s *types.LimitSupport) bool { return s.CPUQuota },
+	},
+}
+
+func checkCgroup(report *types.Report, cfg *config.Config) {
+	// CGROUP_V1 / CGROUP_DRIVER_MISMATCH / CGROUP_CONTROLLERS_MISSING
+	if !cfg.Rules.Cgroup.Enabled {
+		return
+	}
+	if ostype, _ := report.Docker.DaemonInfo["os"].(string); ostype != "" && ostype != "linux" {
+		return
+	}
+	d := report.Docker
+	host := report.Host.Cgroup
+
+	if d.CgroupVersion == "1" {
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "CGROUP_V1",
+			Subject:     "cgroup_version=1",
+			Severity:    "low",
+			Category:    "host",
+			Description: "The host runs cgroup v1, which container runtimes and Kubernetes are phasing out; cgroup v2 brings unified resource accounting and safe delegation for rootless containers",
+			Facts: map[string]interface{}{
+				"cgroup_version": d.CgroupVersion,
+				"cgroup_source":  d.CgroupSource,
+				"kernel":         report.Host.Kernel,
+			},
+			Solutions: []string{
+				"Boot with the unified hierarchy: add 'systemd.unified_cgroup_hierarchy=1' to GRUB_CMDLINE_LINUX, run 'update-grub' (or 'grub2-mkconfig') and reboot.",
+				"Or upgrade to a distribution release that uses cgroup v2 by default.",
+				"cgroup v2 needs Docker 20.10 or later and kernel 4.15 or later (5.2+ recommended); see KERNEL_TOO_OLD.",
+			},
+		})
+	}
+
+	if d.CgroupDriver == "cgroupfs" && host != nil && host.Systemd {
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "CGROUP_DRIVER_MISMATCH",
+			Subject:     "cgroup_driver=cgroupfs",
+			Severity:    "medium",
+			Category:    "configuration",
+			Description: "dockerd uses the cgroupfs cgroup driver on a systemd host; two cgroup managers then manage the same hierarchy, which can become unstable under resource pressure and breaks kubelet setups expecting the systemd driver",
+			Facts: map[string]interface{}{
+				"cgroup_driver":  d.CgroupDriver,
+				"cgroup_version": d.CgroupVersion,
+				"systemd":        true,
+			},
+			Solutions: []string{
+				"Switch to the systemd driver: add \"exec-opts\": [\"native.cgroupdriver=systemd\"] to daemon.json and restart Docker.",
+				"Running containers are moved to the new driver when they are restarted; kubelet's cgroupDriver must use the same driver.",
+			},
+		})
+	}
+
+	var missing []cgroupController
+	for _, c := range cgroupControllers {
+		if c.daemonWarning != "" && daemonWarningReported(report, c.daemonWarning) {
+			continue
+		}
+		switch {
+		case d.CgroupDriver == "none":
+			// Rootless without cgroups: no limit is enforced.
+			missing = append(missing, c)
+		case d.LimitSupport != nil && !c.supported(d.LimitSupport):
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	severity := "low"
+	var names, limits []string
+	var affected []string
+	solutions := []string{}
+	for _, c := range missing {
+		names = append(names, c.name)
+		limits = append(limits, c.limit)
+		if severityRank[c.severity] > severityRank[severity] {
+			severity = c.severity
+		}
+		for _, ct := range report.Containers.List {
+			if ct.Limits != nil && c.uses(ct.Limits) && !containsString(affected, ct.Name) {
+				affected = append(affected, ct.Name)
+				if c.name == "memory" || c.name == "pids" {
+					severity = "high" // containers rely on a limit that is silently ignored
+				}
+			}
+		}
+		solutions = append(solutions, c.solution)
+	}
+	if d.CgroupDriver == "none" {
+		solutions = []string{"Rootless Docker needs cgroup v2 with systemd: delegate controllers with a drop-in for user@.service ('Delegate=cpu cpuset io memory pids') and restart the user's Docker service."}
+	}
+
+	facts := map[string]interface{}{
+		"missing_controllers": names,
+		"cgroup_version":      d.CgroupVersion,
+		"cgroup_driver":       d.CgroupDriver,
+	}
+	if host != nil {
+		facts["host_controllers"] = host.Controllers
+	}
+	if len(affected) > 0 {
+		facts["containers_with_ineffective_limits"] = affected
+	}
+	report.Issues = append(report.Issues, types.Issue{
+		RuleID:      "CGROUP_CONTROLLERS_MISSING",
+		Subject:     "cgroup_controllers=" + strings.Join(names, ","),
+		Severity:    severity,
+		Category:    "host",
+		Description: fmt.Sprintf("cgroup controllers %s are not available to dockerd, so %s are silently ignored", strings.Join(names, ", "), strings.Join(limits, ", ")),
+		Facts:       facts,
+		Solutions:   append(solutions, "Restart Docker after the change and confirm with 'docker info' that the 'No ... support' warnings are gone."),
+	})
+}
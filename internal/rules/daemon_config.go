@@ -0,0 +1,158 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// dockerManagedIface reports whether a route belongs to a Docker-created interface;
+// those routes are expected to fall inside the address pools.
+func dockerManagedIface(iface string) bool {
+	return iface == "docker0" || iface == "docker_gwbridge" || strings.HasPrefix(iface, "br-") || strings.HasPrefix(iface, "veth")
+}
+
+func checkDaemonConfig(report *types.Report, cfg *config.Config) {
+	// DAEMON_CONFIG_INVALID / DAEMON_TCP_WITHOUT_TLS / DAEMON_LOG_ROTATION_MISSING /
+	// DAEMON_LIVE_RESTORE_DISABLED / DAEMON_USERNS_REMAP_DISABLED / DAEMON_ICC_ENABLED /
+	// DAEMON_DEFAULT_ULIMITS_UNSET / DAEMON_ADDRESS_POOL_OVERLAP
+	dc := report.Docker.DaemonConfig
+	if !cfg.Rules.DaemonConfig.Enabled || dc == nil || !dc.Readable {
+		return
+	}
+	add := func(ruleID, severity, description string, facts map[string]interface{}, solutions []string, refs []types.Reference) {
+		if facts == nil {
+			facts = map[string]interface{}{}
+		}
+		facts["config_path"] = dc.Path
+		facts["config_exists"] = dc.Exists
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      ruleID,
+			Subject:     "daemon_config",
+			Severity:    severity,
+			Category:    "configuration",
+			Description: description,
+			Facts:       facts,
+			Solutions:   append(solutions, "Apply daemon.json changes with 'sudo systemctl restart docker' (or 'systemctl reload docker' for reloadable options); validate first with 'dockerd --validate --config-file "+dc.Path+"' (Docker 23+)."),
+			References:  refs,
+		})
+	}
+
+	if dc.ParseError != "" {
+		add("DAEMON_CONFIG_INVALID", "high", fmt.Sprintf("Docker daemon configuration %s cannot be parsed: %s. dockerd will refuse to start on its next restart", dc.Path, dc.ParseError),
+			map[string]interface{}{"parse_error": dc.ParseError},
+			[]string{
+				fmt.Sprintf("Fix the syntax of %s, e.g. check it with 'python3 -m json.tool %s'.", dc.Path, dc.Path),
+				"Values must have the documented types: log-opts values are strings (\"max-file\": \"3\"), booleans are unquoted.",
+			}, nil)
+		// The remaining checks would only report defaults of a file we could not read.
+		return
+	}
+
+	var plainTCP []string
+	for _, h := range dc.Hosts {
+		if strings.HasPrefix(h, "tcp://") {
+			plainTCP = append(plainTCP, h)
+		}
+	}
+	if len(plainTCP) > 0 && !dc.TLSVerify {
+		severity, detail := "high", "without TLS"
+		if dc.TLS {
+			severity, detail = "medium", "with TLS but without client certificate verification (tlsverify)"
+		}
+		add("DAEMON_TCP_WITHOUT_TLS", severity, fmt.Sprintf("Docker API is exposed on %s %s; anyone who can reach it controls the host", strings.Join(plainTCP, ", "), detail),
+			map[string]interface{}{"tcp_hosts": plainTCP, "tls": dc.TLS, "tlsverify": dc.TLSVerify},
+			[]string{
+				"Enable mutual TLS: set \"tlsverify\": true with \"tlscacert\", \"tlscert\" and \"tlskey\", and use port 2376.",
+				"Or remove the tcp:// host and reach the daemon over SSH ('DOCKER_HOST=ssh://user@host').",
+			},
+			cisReference("2.6", "Ensure TLS authentication for Docker daemon is configured"))
+	}
+
+	driver := dc.LogDriver
+	if driver == "" {
+		driver = "json-file"
+	}
+	if driver == "json-file" {
+		var missing []string
+		for _, opt := range []string{"max-size", "max-file"} {
+			if dc.LogOpts[opt] == "" {
+				missing = append(missing, opt)
+			}
+		}
+		if len(missing) > 0 {
+			severity := "low"
+			if dc.LogOpts["max-size"] == "" {
+				severity = "medium" // json-file logs grow without bound
+			}
+			add("DAEMON_LOG_ROTATION_MISSING", severity, fmt.Sprintf("Default json-file logging has no %s; container logs can fill the Docker data root", strings.Join(missing, "/")),
+				map[string]interface{}{"log_driver": driver, "missing_log_opts": missing, "log_opts": dc.LogOpts},
+				[]string{
+					"Set defaults in daemon.json: \"log-opts\": {\"max-size\": \"10m\", \"max-file\": \"3\"}.",
+					"Or switch to the 'local' log driver, which rotates by default: \"log-driver\": \"local\".",
+					"Defaults only apply to containers created afterwards; recreate existing containers to pick them up.",
+				}, nil)
+		}
+	}
+
+	if !dc.LiveRestore {
+		add("DAEMON_LIVE_RESTORE_DISABLED", "low", "live-restore is disabled; restarting or upgrading dockerd stops every running container", nil,
+			[]string{
+				"Set \"live-restore\": true in daemon.json so containers keep running while dockerd restarts.",
+				"live-restore is not supported together with Swarm mode.",
+			},
+			cisReference("2.14", "Ensure live restore is enabled"))
+	}
+
+	if dc.UsernsRemap == "" {
+		add("DAEMON_USERNS_REMAP_DISABLED", "low", "User namespace remapping is not enabled; root in a container is root on the host", nil,
+			[]string{
+				"Set \"userns-remap\": \"default\" in daemon.json to map container root to an unprivileged host user.",
+				"Existing images and volumes are stored per remapped user; plan the switch as a migration.",
+			},
+			cisReference("2.8", "Enable user namespace support"))
+	}
+
+	if dc.ICC == nil || *dc.ICC {
+		add("DAEMON_ICC_ENABLED", "low", "Inter-container communication on the default bridge is enabled; every container on docker0 can reach every other", nil,
+			[]string{
+				"Set \"icc\": false in daemon.json and connect containers that need to talk through user-defined networks.",
+			},
+			cisReference("2.1", "Ensure network traffic is restricted between containers on the default bridge"))
+	}
+
+	if len(dc.DefaultUlimits) == 0 {
+		add("DAEMON_DEFAULT_ULIMITS_UNSET", "low", "No default-ulimits are configured; containers inherit the (often unlimited) limits of the dockerd/containerd service", nil,
+			[]string{
+				"Set defaults in daemon.json, e.g. \"default-ulimits\": {\"nofile\": {\"Name\": \"nofile\", \"Soft\": 65536, \"Hard\": 65536}}.",
+				"Override per container with '--ulimit' where a workload needs more.",
+			},
+			cisReference("2.7", "Ensure the default ulimit is configured appropriately"))
+	}
+
+	var ranges []string
+	if dc.Bip != "" {
+		ranges = append(ranges, dc.Bip)
+	}
+	for _, p := range dc.AddressPools {
+		ranges = append(ranges, p.Base)
+	}
+	var overlaps []string
+	for _, cidr := range ranges {
+		for _, r := range report.Host.Routes {
+			if !dockerManagedIface(r.Iface) && cidrsOverlap(cidr, r.Destination) {
+				overlaps = append(overlaps, fmt.Sprintf("%s overlaps %s via %s", cidr, r.Destination, r.Iface))
+			}
+		}
+	}
+	if len(overlaps) > 0 {
+		add("DAEMON_ADDRESS_POOL_OVERLAP", "high", fmt.Sprintf("Docker address ranges collide with %d host route(s); containers on those networks cannot reach the routed hosts", len(overlaps)),
+			map[string]interface{}{"overlaps": overlaps, "bip": dc.Bip, "default_address_pools": dc.AddressPools},
+			[]string{
+				"Choose \"bip\" and \"default-address-pools\" bases outside every network the host routes to (LAN, VPN, cloud VPC).",
+				"Existing networks keep their subnets; recreate them after changing the pools ('docker network rm' / 'docker compose down && up').",
+			}, nil)
+	}
+}
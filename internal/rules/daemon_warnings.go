@@ -0,0 +1,204 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// daemonWarning classifies a known /info warning by a lower-case substring.
+type daemonWarning struct {
+	key       string
+	match     []string
+	severity  string
+	impact    string
+	solutions []string
+	// supersededBy is a rule that reports the same problem in more detail; the class is
+	// dropped when that rule already raised a finding.
+	supersededBy string
+}
+
+// knownDaemonWarnings are checked in order; the first match wins.
+var knownDaemonWarnings = []daemonWarning{
+	{
+		key:      "api_without_encryption",
+		match:    []string{"without encryption"},
+		severity: "high",
+		impact:   "The Docker API is reachable over plain TCP; anyone who can connect has root on the host.",
+		solutions: []string{
+			"Remove the tcp:// host or enable mutual TLS (\"tlsverify\": true with CA, cert and key) on port 2376.",
+			"Reach the daemon over SSH instead: 'DOCKER_HOST=ssh://user@host'.",
+		},
+		supersededBy: "DAEMON_TCP_WITHOUT_TLS",
+	},
+	{
+		key:      "ip_forward_disabled",
+		match:    []string{"ipv4 forwarding is disabled"},
+		severity: "high",
+		impact:   "Containers on bridge networks cannot reach anything outside the host.",
+		solutions: []string{
+			"Enable forwarding: 'sudo sysctl -w net.ipv4.ip_forward=1' and persist it in /etc/sysctl.d/.",
+			"Check that no other tool (firewall scripts, cloud-init) resets it after boot.",
+		},
+	},
+	{
+		key:      "bridge_nf_call",
+		match:    []string{"bridge-nf-call-iptables is disabled", "bridge-nf-call-ip6tables is disabled"},
+		severity: "medium",
+		impact:   "Traffic between containers on the same bridge bypasses iptables, so published-port rules and network isolation may not apply.",
+		solutions: []string{
+			"Load the module: 'sudo modprobe br_netfilter' and add it to /etc/modules-load.d/.",
+			"Enable it: 'sudo sysctl -w net.bridge.bridge-nf-call-iptables=1 net.bridge.bridge-nf-call-ip6tables=1' and persist it in /etc/sysctl.d/.",
+		},
+	},
+	{
+		key:      "no_memory_limit",
+		match:    []string{"no memory limit support"},
+		severity: "medium",
+		impact:   "Container memory limits (--memory) are not enforced; one container can exhaust host memory.",
+		solutions: []string{
+			"Enable the memory cgroup controller: add 'cgroup_enable=memory' to the kernel command line (common on Raspberry Pi) and reboot.",
+		},
+	},
+	{
+		key:      "no_cpu_limit",
+		match:    []string{"no cpu cfs quota support", "no cpu cfs period support", "no cpu shares support", "no cpuset support"},
+		severity: "medium",
+		impact:   "CPU limits (--cpus, --cpu-shares, --cpuset-cpus) are not enforced.",
+		solutions: []string{
+			"Use a kernel built with CONFIG_CFS_BANDWIDTH and CONFIG_CPUSETS, and make sure the cpu/cpuset cgroup controllers are delegated to Docker.",
+		},
+	},
+	{
+		key:      "no_swap_limit",
+		match:    []string{"no swap limit support"},
+		severity: "low",
+		impact:   "--memory-swap is ignored; containers can use swap beyond their memory limit.",
+		solutions: []string{
+			"On cgroup v1 hosts add 'swapaccount=1' to the kernel command line (GRUB_CMDLINE_LINUX) and reboot; cgroup v2 supports swap limits natively.",
+		},
+	},
+	{
+		key:      "no_pids_limit",
+		match:    []string{"no pids limit support"},
+		severity: "medium",
+		impact:   "--pids-limit is not enforced; a fork bomb in a container can exhaust host PIDs.",
+		solutions: []string{
+			"Enable the pids cgroup controller (CONFIG_CGROUP_PIDS) or move to cgroup v2.",
+		},
+	},
+	{
+		key:      "rootless_without_cgroups",
+		match:    []string{"running in rootless-mode without cgroups"},
+		severity: "medium",
+		impact:   "Resource limits are ignored for every container of this rootless daemon.",
+		solutions: []string{
+			"Use cgroup v2 with systemd and delegate controllers to the user: see the rootless Docker documentation on 'Limiting resources'.",
+		},
+	},
+	{
+		key:      "cgroup_v1_deprecated",
+		match:    []string{"cgroup v1 is deprecated"},
+		severity: "low",
+		impact:   "Support for cgroup v1 will be removed in a future Docker Engine release.",
+		solutions: []string{
+			"Boot the host with the unified hierarchy ('systemd.unified_cgroup_hierarchy=1') or upgrade to a distribution that uses cgroup v2 by default.",
+		},
+	},
+	{
+		key:      "storage_driver_deprecated",
+		match:    []string{"storage-driver is deprecated", "storage driver is deprecated", "loopback devices", "d_type"},
+		severity: "medium",
+		impact:   "The storage driver setup is deprecated or unsupported; see STORAGE_DRIVER_UNSUPPORTED.",
+		solutions: []string{
+			"Migrate to overlay2 on an ext4 or xfs (ftype=1) backing filesystem.",
+		},
+	},
+}
+
+func checkDaemonWarnings(report *types.Report, cfg *config.Config) {
+	// DAEMON_WARNINGS
+	if !cfg.Rules.DaemonWarnings.Enabled || len(report.Docker.Warnings) == 0 {
+		return
+	}
+
+	grouped := map[string][]string{}
+	for _, w := range report.Docker.Warnings {
+		key := "other"
+		lower := strings.ToLower(w)
+	match:
+		for _, known := range knownDaemonWarnings {
+			for _, m := range known.match {
+				if strings.Contains(lower, m) {
+					key = known.key
+					break match
+				}
+			}
+		}
+		grouped[key] = append(grouped[key], w)
+	}
+
+	keys := make([]string, 0, len(grouped))
+	for k := range grouped {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		warnings := grouped[key]
+		w := daemonWarning{
+			key:      "other",
+			severity: "low",
+			impact:   "The Docker daemon reported a warning that docker-doctor does not classify.",
+			solutions: []string{
+				"Check 'docker info' and the dockerd logs ('journalctl -u docker') for details.",
+			},
+		}
+		for _, known := range knownDaemonWarnings {
+			if known.key == key {
+				w = known
+				break
+			}
+		}
+		if w.supersededBy != "" && hasRuleIssue(report, w.supersededBy) {
+			continue
+		}
+
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "DAEMON_WARNINGS",
+			Subject:     "daemon_warning=" + key,
+			Severity:    w.severity,
+			Category:    "configuration",
+			Description: fmt.Sprintf("Docker daemon reports: %s. %s", strings.Join(warnings, "; "), w.impact),
+			Facts: map[string]interface{}{
+				"warning_class": key,
+				"warnings":      warnings,
+			},
+			Solutions: append(append([]string{}, w.solutions...), "Restart the daemon and confirm the warning is gone from 'docker info'."),
+		})
+	}
+}
+
+// hasRuleIssue reports whether report already holds a finding of ruleID.
+func hasRuleIssue(report *types.Report, ruleID string) bool {
+	for _, is := range report.Issues {
+		if is.RuleID == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// daemonWarningReported reports whether checkDaemonWarnings already raised the given
+// warning class, so rules that detect the same problem on their own can stay quiet.
+func daemonWarningReported(report *types.Report, key string) bool {
+	for _, is := range report.Issues {
+		if is.RuleID == "DAEMON_WARNINGS" && is.Subject == "daemon_warning="+key {
+			return true
+		}
+	}
+	return false
+}
@@ -8,12 +8,47 @@ import (
 	"github.com/dashu-baba/docker-doctor/internal/types"
 )
 
+// diskThresholds resolves the warning/critical thresholds of a filesystem. Configured
+// paths override the rule defaults; when several configured paths share the filesystem
+// the strictest threshold wins. critical is 0 when none is configured.
+func diskThresholds(rule config.DiskUsageRule, path string, aliases []string) (warning, critical int) {
+	warning, critical = rule.Threshold, rule.Critical
+	overridden := false
+	for _, p := range rule.Paths {
+		if p.Path != path && !containsString(aliases, p.Path) {
+			continue
+		}
+		if p.Warning > 0 && (!overridden || p.Warning < warning) {
+			warning = p.Warning
+			overridden = true
+		}
+		if p.Critical > 0 && (critical == 0 || p.Critical < critical) {
+			critical = p.Critical
+		}
+	}
+	return warning, critical
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 func checkDiskUsage(report *types.Report, cfg *config.Config) {
 	// DISK_USAGE_HIGH
 	for path, disk := range report.Host.DiskUsage {
-		if disk.UsedPercent > float64(cfg.Rules.DiskUsage.Threshold) {
+		warning, critical := diskThresholds(cfg.Rules.DiskUsage, path, disk.Paths)
+		if disk.UsedPercent > float64(warning) {
 			severity := "medium"
-			if disk.UsedPercent > 90 {
+			if critical > 0 {
+				if disk.UsedPercent >= float64(critical) {
+					severity = "high"
+				}
+			} else if disk.UsedPercent > 90 {
 				severity = "high"
 			} else if disk.UsedPercent < 85 {
 				severity = "low"
@@ -24,7 +59,17 @@ func checkDiskUsage(report *types.Report, cfg *config.Config) {
 				"used_bytes":   disk.Used,
 				"total_bytes":  disk.Total,
 				"used_percent": disk.UsedPercent,
-				"threshold":    cfg.Rules.DiskUsage.Threshold,
+				"threshold":    warning,
+			}
+			if critical > 0 {
+				facts["critical_threshold"] = critical
+			}
+			if disk.MountPoint != "" {
+				facts["mount_point"] = disk.MountPoint
+				facts["fs_type"] = disk.FSType
+			}
+			if len(disk.Paths) > 0 {
+				facts["same_filesystem_paths"] = disk.Paths
 			}
 
 			solutions := []string{
@@ -32,13 +77,18 @@ func checkDiskUsage(report *types.Report, cfg *config.Config) {
 				"Consider increasing disk space if possible.",
 			}
 
-			if path == "/var/lib/docker" || strings.Contains(path, "docker") {
+			onDocker := path == "/var/lib/docker" || strings.Contains(path, "docker")
+			for _, p := range disk.Paths {
+				onDocker = onDocker || strings.Contains(p, "docker")
+			}
+			if onDocker {
 				solutions = append(solutions,
 					"Run 'docker system prune' to remove unused containers, images, and networks.",
 					"Run 'docker volume prune' to remove unused volumes.",
 					"Inspect and clean up large Docker images or logs.",
 				)
-			} else if path == "/" {
+			}
+			if path == "/" {
 				solutions = append(solutions,
 					"Check for large log files in /var/log and rotate them.",
 					"Remove old kernel packages: 'apt autoremove' (on Ubuntu/Debian).",
@@ -50,7 +100,7 @@ func checkDiskUsage(report *types.Report, cfg *config.Config) {
 				Subject:     "path=" + path,
 				Severity:    severity,
 				Category:    "disk_usage",
-				Description: fmt.Sprintf("Disk usage for %s is %.2f%%, exceeding threshold of %d%%", path, disk.UsedPercent, cfg.Rules.DiskUsage.Threshold),
+				Description: fmt.Sprintf("Disk usage for %s is %.2f%%, exceeding threshold of %d%%", path, disk.UsedPercent, warning),
 				Facts:       facts,
 				Solutions:   solutions,
 			})
@@ -0,0 +1,147 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/lifecycle"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+var severityRank = map[string]int{"low": 1, "medium": 2, "high": 3}
+
+func checkEngineVersion(report *types.Report, cfg *config.Config) {
+	// ENGINE_VERSION_OUTDATED
+	rule := cfg.Rules.EngineVersion
+	version := report.Docker.Version
+	if !rule.Enabled || version == "" {
+		return
+	}
+	source := "embedded"
+	table, err := lifecycle.Load(rule.LifecycleFile)
+	if err != nil {
+		// The scan command rejects unreadable files up front; fall back for library callers.
+		table, _ = lifecycle.Default()
+	} else if rule.LifecycleFile != "" {
+		source = rule.LifecycleFile
+	}
+	if table == nil {
+		return
+	}
+
+	series, known := table.Lookup(version)
+	eol := (known && series.EOL) || (!known && table.OlderThanAll(version))
+	issues := table.IssuesFor(version)
+
+	facts := map[string]interface{}{
+		"engine_version":    version,
+		"lifecycle_source":  source,
+		"lifecycle_updated": table.Updated,
+	}
+	var reasons []string
+	severity := ""
+	raise := func(s string) {
+		if severityRank[s] > severityRank[severity] {
+			severity = s
+		}
+	}
+	if known {
+		facts["series"] = series.Series
+		facts["series_release_date"] = series.ReleaseDate
+		facts["api_version"] = series.APIVersion
+	}
+	if eol {
+		facts["end_of_life"] = true
+		when := ""
+		if known && series.EOLDate != "" {
+			facts["eol_date"] = series.EOLDate
+			when = " since " + series.EOLDate
+		}
+		reasons = append(reasons, "the release line is end-of-life"+when+" and no longer receives security fixes")
+		raise("medium")
+	}
+	var ids []string
+	var affectedRules []string
+	for _, is := range issues {
+		ids = append(ids, is.ID)
+		reasons = append(reasons, fmt.Sprintf("%s: %s", is.ID, is.Summary))
+		affectedRules = append(affectedRules, is.AffectsRules...)
+		raise(is.Severity)
+	}
+	if len(ids) > 0 {
+		facts["known_issues"] = ids
+	}
+	if len(affectedRules) > 0 {
+		facts["affected_rules"] = affectedRules
+	}
+
+	stale, age := false, 0
+	if updated := table.UpdatedAt(); rule.MaxTableAgeDays > 0 && !report.Timestamp.IsZero() && !updated.IsZero() {
+		age = int(report.Timestamp.Sub(updated).Hours() / 24)
+		if age > rule.MaxTableAgeDays {
+			stale = true
+			facts["lifecycle_table_age_days"] = age
+		}
+	}
+	staleSolution := "The lifecycle table is older than rules.engine_version.max_table_age_days; update docker-doctor or pass a newer table with --lifecycle-file."
+	if severity == "" {
+		if stale {
+			// Nothing known against this version, but the table may predate its EOL or advisories.
+			report.Issues = append(report.Issues, types.Issue{
+				RuleID:      "ENGINE_VERSION_OUTDATED",
+				Subject:     "engine_version=" + version,
+				Severity:    "low",
+				Category:    "configuration",
+				Description: fmt.Sprintf("Docker Engine %s could not be checked reliably: the lifecycle table is %d days old (updated %s) and may miss newer end-of-life dates and advisories", version, age, table.Updated),
+				Facts:       facts,
+				Solutions:   []string{staleSolution},
+			})
+		}
+		return
+	}
+
+	latest := table.Series[len(table.Series)-1]
+	solutions := []string{
+		fmt.Sprintf("Upgrade Docker Engine to the latest patch release of a maintained series (newest known: %s.x); see https://docs.docker.com/engine/install/.", latest.Series),
+		"Check the release notes for breaking changes before upgrading: https://docs.docker.com/engine/release-notes/",
+	}
+	var refs []types.Reference
+	for _, is := range issues {
+		if is.URL != "" {
+			refs = append(refs, types.Reference{Kind: "advisory", Label: is.ID + ": " + is.Summary, URL: is.URL})
+		}
+	}
+	if stale {
+		solutions = append(solutions, staleSolution)
+	}
+
+	report.Issues = append(report.Issues, types.Issue{
+		RuleID:      "ENGINE_VERSION_OUTDATED",
+		Subject:     "engine_version=" + version,
+		Severity:    severity,
+		Category:    "configuration",
+		Description: fmt.Sprintf("Docker Engine %s is outdated: %s", version, strings.Join(reasons, "; ")),
+		Facts:       facts,
+		Solutions:   solutions,
+		References:  refs,
+	})
+
+	// Point findings of other rules at the engine bugs that can skew them.
+	for i := range report.Issues {
+		var related []string
+		for _, is := range issues {
+			for _, r := range is.AffectsRules {
+				if r == report.Issues[i].RuleID {
+					related = append(related, is.ID)
+				}
+			}
+		}
+		if len(related) > 0 {
+			if report.Issues[i].Facts == nil {
+				report.Issues[i].Facts = map[string]interface{}{}
+			}
+			report.Issues[i].Facts["engine_known_issues"] = related
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// hostMemoryUsedPercent is MemTotal - MemAvailable as a percentage of MemTotal.
+func hostMemoryUsedPercent(res *types.HostResources) float64 {
+	if res.MemTotal == 0 || res.MemAvailable > res.MemTotal {
+		return 0
+	}
+	return float64(res.MemTotal-res.MemAvailable) / float64(res.MemTotal) * 100
+}
+
+// hostMemoryPressured reports whether host memory crossed the configured thresholds
+// (90% used when no threshold is configured). Used to attribute OOM kills.
+func hostMemoryPressured(res *types.HostResources, rule config.HostResourcesRule) bool {
+	if res == nil {
+		return false
+	}
+	limit := rule.MemoryPercent
+	if limit <= 0 {
+		limit = 90
+	}
+	if hostMemoryUsedPercent(res) >= float64(limit) {
+		return true
+	}
+	return rule.PSIThreshold > 0 && res.PSIMemory != nil && res.PSIMemory.SomeAvg60 >= rule.PSIThreshold
+}
+
+func checkHostResources(report *types.Report, cfg *config.Config) {
+	// HOST_MEMORY_PRESSURE / HOST_SWAP_HEAVY / HOST_LOAD_HIGH
+	rule := cfg.Rules.HostResources
+	res := report.Host.Resources
+	if !rule.Enabled || res == nil {
+		return
+	}
+
+	used := hostMemoryUsedPercent(res)
+	memHigh := rule.MemoryPercent > 0 && used >= float64(rule.MemoryPercent)
+	memStalled := rule.PSIThreshold > 0 && res.PSIMemory != nil && res.PSIMemory.SomeAvg60 >= rule.PSIThreshold
+	if memHigh || memStalled {
+		severity := "medium"
+		if used >= 95 || (rule.PSIThreshold > 0 && res.PSIMemory != nil && res.PSIMemory.FullAvg60 >= rule.PSIThreshold) {
+			severity = "high"
+		}
+		factsMap := map[string]interface{}{
+			"mem_total":     res.MemTotal,
+			"mem_available": res.MemAvailable,
+			"used_percent":  used,
+			"threshold":     rule.MemoryPercent,
+			"oom_kills":     res.OOMKills,
+		}
+		desc := fmt.Sprintf("Host memory is %.1f%% used (%s available of %s)", used, humanBytes(res.MemAvailable), humanBytes(res.MemTotal))
+		if res.PSIMemory != nil {
+			factsMap["psi_memory_some_avg60"] = res.PSIMemory.SomeAvg60
+			factsMap["psi_memory_full_avg60"] = res.PSIMemory.FullAvg60
+			factsMap["psi_threshold"] = rule.PSIThreshold
+			desc += fmt.Sprintf("; tasks stalled on memory %.1f%% of the last minute", res.PSIMemory.SomeAvg60)
+		}
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "HOST_MEMORY_PRESSURE",
+			Subject:     "host_memory",
+			Severity:    severity,
+			Category:    "host_resources",
+			Description: desc,
+			Facts:       factsMap,
+			Solutions: []string{
+				"Find the largest consumers: 'docker stats --no-stream' and 'ps aux --sort=-rss | head'",
+				"Set memory limits on containers so one workload cannot starve the host (see CONTAINER_NO_MEMORY_LIMIT).",
+				fmt.Sprintf("The kernel has OOM-killed %d processes since boot ('grep oom_kill /proc/vmstat'); check 'dmesg' for victims.", res.OOMKills),
+			},
+		})
+	}
+
+	if rule.SwapPercent > 0 && res.SwapTotal > 0 && res.SwapFree <= res.SwapTotal {
+		used := float64(res.SwapTotal-res.SwapFree) / float64(res.SwapTotal) * 100
+		if used >= float64(rule.SwapPercent) {
+			report.Issues = append(report.Issues, types.Issue{
+				RuleID:      "HOST_SWAP_HEAVY",
+				Subject:     "host_swap",
+				Severity:    "medium",
+				Category:    "host_resources",
+				Description: fmt.Sprintf("Host swap is %.1f%% used (%s of %s)", used, humanBytes(res.SwapTotal-res.SwapFree), humanBytes(res.SwapTotal)),
+				Facts: map[string]interface{}{
+					"swap_total":   res.SwapTotal,
+					"swap_free":    res.SwapFree,
+					"used_percent": used,
+					"threshold":    rule.SwapPercent,
+					"pswpin":       res.PswpIn,
+					"pswpout":      res.PswpOut,
+				},
+				Solutions: []string{
+					"Check whether the host is actively swapping: 'vmstat 1' (si/so columns).",
+					"Swapped-out memory makes containers slow rather than OOM-killed; reduce memory usage or add RAM.",
+					"Cap container swap with '--memory-swap' (see CONTAINER_SWAP_UNLIMITED).",
+				},
+			})
+		}
+	}
+
+	if res.NCPU > 0 {
+		perCPU := res.Load5 / float64(res.NCPU)
+		loadHigh := rule.LoadPerCPU > 0 && perCPU >= rule.LoadPerCPU
+		cpuStalled := rule.PSIThreshold > 0 && res.PSICPU != nil && res.PSICPU.SomeAvg60 >= rule.PSIThreshold
+		if loadHigh || cpuStalled {
+			severity := "medium"
+			if rule.LoadPerCPU > 0 && perCPU >= rule.LoadPerCPU*2 {
+				severity = "high"
+			}
+			factsMap := map[string]interface{}{
+				"load1":        res.Load1,
+				"load5":        res.Load5,
+				"load15":       res.Load15,
+				"ncpu":         res.NCPU,
+				"load_per_cpu": perCPU,
+				"threshold":    rule.LoadPerCPU,
+			}
+			desc := fmt.Sprintf("Host load average is %.2f over 5 minutes on %d CPUs (%.2f per CPU)", res.Load5, res.NCPU, perCPU)
+			if res.PSICPU != nil {
+				factsMap["psi_cpu_some_avg60"] = res.PSICPU.SomeAvg60
+				desc += fmt.Sprintf("; runnable tasks waited for CPU %.1f%% of the last minute", res.PSICPU.SomeAvg60)
+			}
+			if res.PSIIO != nil {
+				factsMap["psi_io_some_avg60"] = res.PSIIO.SomeAvg60
+			}
+			report.Issues = append(report.Issues, types.Issue{
+				RuleID:      "HOST_LOAD_HIGH",
+				Subject:     "host_load",
+				Severity:    severity,
+				Category:    "host_resources",
+				Description: desc,
+				Facts:       factsMap,
+				Solutions: []string{
+					"Find busy containers: 'docker stats --no-stream'",
+					"Load also counts tasks blocked on disk I/O; compare with I/O pressure ('cat /proc/pressure/io').",
+					"Limit CPU of batch workloads with '--cpus' so latency-sensitive services keep headroom.",
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/facts"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// imageView is an image with its container count and unique size resolved from the
+// image list, /system/df and the container list (whichever has the data).
+type imageView struct {
+	ID          string
+	Tags        []string
+	Created     time.Time
+	Containers  int64
+	Unique      uint64 // bytes freed by removing only this image
+	SharedKnown bool
+}
+
+func (v imageView) shortID() string {
+	id := strings.TrimPrefix(v.ID, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
+}
+
+func (v imageView) label() string {
+	if len(v.Tags) > 0 {
+		return v.Tags[0]
+	}
+	return v.shortID()
+}
+
+// buildImageViews resolves per-image data. Intermediate images (parents of other images,
+// from the classic builder) are skipped: they are removed together with their children.
+func buildImageViews(report *types.Report, df *facts.DockerSystemDfSummary) []imageView {
+	dfByID := map[string]facts.DfImage{}
+	if df != nil {
+		for _, im := range df.Images {
+			dfByID[im.ID] = im
+		}
+	}
+	usedBy := map[string]int64{}
+	for _, c := range report.Containers.List {
+		if c.ImageID != "" {
+			usedBy[c.ImageID]++
+		}
+	}
+	parents := map[string]bool{}
+	for _, im := range report.Images.List {
+		if im.ParentID != "" {
+			parents[im.ParentID] = true
+		}
+	}
+
+	views := make([]imageView, 0, len(report.Images.List))
+	for _, im := range report.Images.List {
+		if parents[im.ID] {
+			continue
+		}
+		shared := im.SharedSize
+		containers := im.Containers
+		if d, ok := dfByID[im.ID]; ok {
+			if shared < 0 {
+				shared = d.SharedSize
+			}
+			if containers < 0 {
+				containers = d.Containers
+			}
+		}
+		if containers < 0 {
+			containers = usedBy[im.ID]
+		}
+
+		v := imageView{ID: im.ID, Created: im.Created, Containers: containers, Unique: im.Size}
+		if shared >= 0 && uint64(shared) <= im.Size {
+			v.Unique = im.Size - uint64(shared)
+			v.SharedKnown = true
+		}
+		for _, t := range im.RepoTags {
+			if t != "<none>:<none>" {
+				v.Tags = append(v.Tags, t)
+			}
+		}
+		views = append(views, v)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].ID < views[j].ID })
+	return views
+}
+
+// repositoryOf returns the repository part of "repo:tag", keeping registry ports intact.
+func repositoryOf(tag string) string {
+	if i := strings.LastIndex(tag, ":"); i > strings.LastIndex(tag, "/") {
+		return tag[:i]
+	}
+	return tag
+}
+
+func checkImages(report *types.Report, cfg *config.Config, df *facts.DockerSystemDfSummary) {
+	// IMAGE_DANGLING / IMAGE_UNUSED / IMAGE_VERSIONS_PILEUP
+	if !cfg.Rules.Images.Enabled || len(report.Images.List) == 0 {
+		return
+	}
+	checkImagesAt(report, cfg, df, time.Now())
+}
+
+func checkImagesAt(report *types.Report, cfg *config.Config, df *facts.DockerSystemDfSummary, now time.Time) {
+	rule := cfg.Rules.Images
+	views := buildImageViews(report, df)
+
+	severityFor := func(reclaimable uint64) string {
+		if rule.SizeThreshold > 0 && reclaimable > rule.SizeThreshold {
+			return "medium"
+		}
+		return "low"
+	}
+	sharedKnown := true
+	for _, v := range views {
+		sharedKnown = sharedKnown && v.SharedKnown
+	}
+	sizeNote := "Reclaimable sizes count only layers unique to each image (shared layers stay while another image uses them)."
+	if !sharedKnown {
+		sizeNote = "Shared layer sizes were not reported by the daemon for some images; their reclaimable size is an upper bound."
+	}
+
+	// Dangling images
+	var dangling []imageView
+	danglingInUse := 0
+	var danglingBytes uint64
+	for _, v := range views {
+		if len(v.Tags) > 0 {
+			continue
+		}
+		if v.Containers > 0 {
+			danglingInUse++
+			continue
+		}
+		dangling = append(dangling, v)
+		danglingBytes += v.Unique
+	}
+	if len(dangling) > 0 {
+		actions := make([]types.Action, 0, len(dangling))
+		for _, v := range dangling {
+			actions = append(actions, types.Action{Type: "image_remove", Target: v.ID, Risk: "safe"})
+		}
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "IMAGE_DANGLING",
+			Subject:     "images_dangling",
+			Severity:    severityFor(danglingBytes),
+			Category:    "storage_bloat",
+			Description: fmt.Sprintf("Found %d dangling (untagged) images not used by any container, reclaimable: %s", len(dangling), humanBytes(danglingBytes)),
+			Facts: map[string]interface{}{
+				"dangling_images":        len(dangling),
+				"dangling_images_in_use": danglingInUse,
+				"reclaimable_bytes":      danglingBytes,
+				"top_images":             topImageViews(dangling, 5, now),
+			},
+			Solutions: []string{
+				"Remove dangling images: 'docker image prune -f'",
+				"Dangling images are left behind when a tag is moved to a newer build; they are safe to remove.",
+				sizeNote,
+			},
+			Actions: actions,
+		})
+	}
+
+	// Tagged images created more than N days ago and not used by any container now.
+	// Docker records no last-use time for images, so age is measured from creation.
+	minAge := time.Duration(rule.UnusedDays) * 24 * time.Hour
+	var unused []imageView
+	var unusedBytes uint64
+	for _, v := range views {
+		if len(v.Tags) == 0 || v.Containers != 0 || now.Sub(v.Created) < minAge {
+			continue
+		}
+		unused = append(unused, v)
+		unusedBytes += v.Unique
+	}
+	if len(unused) > 0 {
+		var actions []types.Action
+		for _, v := range unused {
+			for _, t := range v.Tags {
+				actions = append(actions, types.Action{Type: "image_remove", Target: t, Risk: "planned"})
+			}
+		}
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "IMAGE_UNUSED",
+			Subject:     "images_unused",
+			Severity:    severityFor(unusedBytes),
+			Category:    "storage_bloat",
+			Description: fmt.Sprintf("Found %d tagged images created more than %d days ago and unused by any container now, reclaimable: %s", len(unused), rule.UnusedDays, humanBytes(unusedBytes)),
+			Facts: map[string]interface{}{
+				"unused_images":          len(unused),
+				"created_more_than_days": rule.UnusedDays,
+				"reclaimable_bytes":      unusedBytes,
+				"top_images":             topImageViews(unused, 10, now),
+			},
+			Solutions: []string{
+				"Remove specific images: 'docker image rm <repo:tag>'",
+				fmt.Sprintf("Remove all unused images created more than %d days ago: 'docker image prune -a -f --filter until=%dh'", rule.UnusedDays, rule.UnusedDays*24),
+				"Images removed here must be pulled again before they can be used.",
+				sizeNote,
+			},
+			Actions: actions,
+		})
+	}
+
+	// Many versions of the same repository piling up
+	if rule.MaxVersions <= 0 {
+		return
+	}
+	byRepo := map[string][]imageView{}
+	repoTags := map[string]map[string][]string{} // repo -> image ID -> tags in repo
+	for _, v := range views {
+		for _, t := range v.Tags {
+			repo := repositoryOf(t)
+			if repoTags[repo] == nil {
+				repoTags[repo] = map[string][]string{}
+			}
+			if _, seen := repoTags[repo][v.ID]; !seen {
+				byRepo[repo] = append(byRepo[repo], v)
+			}
+			repoTags[repo][v.ID] = append(repoTags[repo][v.ID], t)
+		}
+	}
+	repos := make([]string, 0, len(byRepo))
+	for repo := range byRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		versions := byRepo[repo]
+		if len(versions) <= rule.MaxVersions {
+			continue
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i].Created.After(versions[j].Created) })
+
+		var excessBytes uint64
+		var actions []types.Action
+		var excess []string
+		for _, v := range versions[rule.MaxVersions:] {
+			excess = append(excess, strings.Join(repoTags[repo][v.ID], ","))
+			if v.Containers != 0 {
+				continue
+			}
+			excessBytes += v.Unique
+			for _, t := range repoTags[repo][v.ID] {
+				actions = append(actions, types.Action{Type: "image_remove", Target: t, Risk: "planned"})
+			}
+		}
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "IMAGE_VERSIONS_PILEUP",
+			Subject:     "repository=" + repo,
+			Severity:    severityFor(excessBytes),
+			Category:    "storage_bloat",
+			Description: fmt.Sprintf("Repository %s has %d image versions (max %d); older unused versions could free %s", repo, len(versions), rule.MaxVersions, humanBytes(excessBytes)),
+			Facts: map[string]interface{}{
+				"repository":        repo,
+				"versions":          len(versions),
+				"max_versions":      rule.MaxVersions,
+				"excess_versions":   excess,
+				"reclaimable_bytes": excessBytes,
+			},
+			Solutions: []string{
+				fmt.Sprintf("List versions: 'docker image ls %s'", repo),
+				fmt.Sprintf("Remove old versions: 'docker image rm %s:<old-tag>'", repo),
+				"Consider pruning old tags in CI/CD after each deploy, keeping only the last few for rollback.",
+				sizeNote,
+			},
+			Actions: actions,
+		})
+	}
+}
+
+// topImageViews formats the n images with the largest unique size.
+func topImageViews(views []imageView, n int, now time.Time) []string {
+	sorted := append([]imageView(nil), views...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Unique != sorted[j].Unique {
+			return sorted[i].Unique > sorted[j].Unique
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	var out []string
+	for i, v := range sorted {
+		if i >= n {
+			break
+		}
+		out = append(out, fmt.Sprintf("%s (%s, %d days old)", v.label(), humanBytes(v.Unique), int(now.Sub(v.Created).Hours()/24)))
+	}
+	return out
+}
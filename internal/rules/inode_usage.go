@@ -0,0 +1,68 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func checkInodeUsage(report *types.Report, cfg *config.Config) {
+	// INODE_USAGE_HIGH
+	rule := cfg.Rules.InodeUsage
+	if !rule.Enabled {
+		return
+	}
+	paths := make([]string, 0, len(report.Host.DiskUsage))
+	for path := range report.Host.DiskUsage {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		disk := report.Host.DiskUsage[path]
+		if disk == nil || disk.InodesTotal == 0 || disk.InodesUsedPercent < float64(rule.Threshold) {
+			continue
+		}
+		severity := "medium"
+		if rule.CriticalThreshold > 0 && disk.InodesUsedPercent >= float64(rule.CriticalThreshold) {
+			severity = "high"
+		}
+
+		solutions := []string{
+			fmt.Sprintf("Find directories holding the most inodes: 'du --inodes -x %s 2>/dev/null | sort -n | tail -20'", path),
+			"When inodes run out, writes fail with 'No space left on device' even though 'df -h' shows free space; check 'df -i'.",
+		}
+		var actions []types.Action
+		if path == "/var/lib/docker" || strings.Contains(path, "docker") || path == "/" {
+			solutions = append(solutions,
+				"Every overlay2 image layer and container writable layer is a full directory tree; images with many small files (node_modules, pip/npm caches) multiply inode use.",
+				"Frequent builds leave many small layers behind: prune build cache with 'docker builder prune --filter until=168h' and dangling images with 'docker image prune'.",
+				"Remove stopped containers ('docker container prune'): each keeps its writable layer and the layers of its image.",
+				"ext4 fixes the inode count at mkfs time; for a dedicated Docker filesystem use XFS (dynamic inodes) or 'mkfs.ext4 -i 8192'.",
+			)
+			actions = append(actions, types.Action{Type: "builder_prune", Risk: "safe", Params: map[string]string{"until": "168h"}})
+		}
+
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "INODE_USAGE_HIGH",
+			Subject:     "path=" + path,
+			Severity:    severity,
+			Category:    "disk_usage",
+			Description: fmt.Sprintf("Inode usage for %s is %.2f%% (%d of %d), exceeding threshold of %d%%; bytes used: %.2f%%", path, disk.InodesUsedPercent, disk.InodesUsed, disk.InodesTotal, rule.Threshold, disk.UsedPercent),
+			Facts: map[string]interface{}{
+				"path":                path,
+				"inodes_used":         disk.InodesUsed,
+				"inodes_total":        disk.InodesTotal,
+				"inodes_used_percent": disk.InodesUsedPercent,
+				"bytes_used_percent":  disk.UsedPercent,
+				"threshold":           rule.Threshold,
+				"critical_threshold":  rule.CriticalThreshold,
+			},
+			Solutions: solutions,
+			Actions:   actions,
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// LimitTotals aggregates the limits of running containers against host capacity.
+// Ratios are 0 when the host capacity is unknown.
+type LimitTotals struct {
+	MemoryLimitBytes   uint64
+	HostMemoryBytes    uint64
+	MemoryRatio        float64
+	CPULimitCores      float64
+	HostCPUs           int
+	CPURatio           float64
+	Running            int
+	WithoutMemoryLimit int
+	WithoutCPULimit    int
+	LimitsUnknown      int // running containers whose HostConfig could not be read
+}
+
+// ComputeLimitTotals sums memory and CPU limits of running containers. Containers
+// without a limit are counted separately: they can use the whole host.
+func ComputeLimitTotals(report *types.Report) LimitTotals {
+	t := LimitTotals{HostCPUs: report.Docker.NCPU}
+	if report.Docker.MemTotal > 0 {
+		t.HostMemoryBytes = uint64(report.Docker.MemTotal)
+	}
+	for _, c := range report.Containers.List {
+		if !isRunning(c) {
+			continue
+		}
+		t.Running++
+		if c.Limits == nil {
+			t.LimitsUnknown++
+			continue
+		}
+		if c.Limits.Memory > 0 {
+			t.MemoryLimitBytes += uint64(c.Limits.Memory)
+		} else {
+			t.WithoutMemoryLimit++
+		}
+		if cores := cpuLimitCores(c.Limits); cores > 0 {
+			t.CPULimitCores += cores
+		} else {
+			t.WithoutCPULimit++
+		}
+	}
+	if t.HostMemoryBytes > 0 {
+		t.MemoryRatio = float64(t.MemoryLimitBytes) / float64(t.HostMemoryBytes)
+	}
+	if t.HostCPUs > 0 {
+		t.CPURatio = t.CPULimitCores / float64(t.HostCPUs)
+	}
+	return t
+}
+
+func isRunning(c types.ContainerInfo) bool {
+	return strings.HasPrefix(c.Status, "Up")
+}
+
+// cpuLimitCores converts --cpus or --cpu-quota/--cpu-period to cores; 0 when unlimited.
+func cpuLimitCores(l *types.ResourceLimits) float64 {
+	switch {
+	case l.NanoCPUs > 0:
+		return float64(l.NanoCPUs) / 1e9
+	case l.CPUQuota > 0:
+		period := l.CPUPeriod
+		if period <= 0 {
+			period = 100000 // kernel default CFS period (100ms)
+		}
+		return float64(l.CPUQuota) / float64(period)
+	}
+	return 0
+}
+
+func checkContainerLimits(report *types.Report, cfg *config.Config) {
+	// CONTAINER_NO_MEMORY_LIMIT / CONTAINER_NO_PIDS_LIMIT / CONTAINER_SWAP_UNLIMITED
+	rule := cfg.Rules.Limits
+	if !rule.Enabled {
+		return
+	}
+
+	for _, container := range report.Containers.List {
+		l := container.Limits
+		if l == nil || !isRunning(container) {
+			continue
+		}
+		baseFacts := func() map[string]interface{} {
+			return map[string]interface{}{
+				"container_id":   container.ID,
+				"container_name": container.Name,
+			}
+		}
+
+		if l.Memory <= 0 {
+			report.Issues = append(report.Issues, types.Issue{
+				RuleID:      "CONTAINER_NO_MEMORY_LIMIT",
+				Subject:     "container=" + container.ID,
+				Severity:    "low",
+				Category:    "limits",
+				Description: fmt.Sprintf("Container %s (%s) has no memory limit and can use all host memory", container.Name, container.ID),
+				Facts:       baseFacts(),
+				Solutions: []string{
+					fmt.Sprintf("Set a limit: 'docker update --memory <size> --memory-swap <size> %s'", container.ID),
+					"In compose: 'deploy.resources.limits.memory' (or 'mem_limit').",
+					"Without a limit a leak in one container triggers the host OOM killer, which may pick another process.",
+				},
+			})
+		}
+
+		if l.PidsLimit <= 0 {
+			f := baseFacts()
+			for _, u := range l.Ulimits {
+				if u.Name == "nproc" {
+					f["ulimit_nproc"] = u.Soft
+				}
+			}
+			report.Issues = append(report.Issues, types.Issue{
+				RuleID:      "CONTAINER_NO_PIDS_LIMIT",
+				Subject:     "container=" + container.ID,
+				Severity:    "low",
+				Category:    "limits",
+				Description: fmt.Sprintf("Container %s (%s) has no PIDs limit; a fork bomb or thread leak can exhaust host PIDs", container.Name, container.ID),
+				Facts:       f,
+				Solutions: []string{
+					fmt.Sprintf("Set a limit: 'docker update --pids-limit <n> %s'", container.ID),
+					"In compose: 'pids_limit' (or 'deploy.resources.limits.pids').",
+				},
+			})
+		}
+
+		if l.Memory > 0 && l.MemorySwap < 0 {
+			f := baseFacts()
+			f["memory_limit"] = l.Memory
+			f["memory_swap"] = l.MemorySwap
+			report.Issues = append(report.Issues, types.Issue{
+				RuleID:      "CONTAINER_SWAP_UNLIMITED",
+				Subject:     "container=" + container.ID,
+				Severity:    "medium",
+				Category:    "limits",
+				Description: fmt.Sprintf("Container %s (%s) has a %s memory limit but unlimited swap; it can swap heavily instead of being OOM-killed", container.Name, container.ID, humanBytes(uint64(l.Memory))),
+				Facts:       f,
+				Solutions: []string{
+					fmt.Sprintf("Cap swap: 'docker update --memory-swap <memory+swap> %s' (equal to --memory disables swap)", container.ID),
+					"Heavy swapping slows the whole host, not only this container.",
+				},
+			})
+		}
+	}
+}
+
+func checkLimitsOvercommit(report *types.Report, cfg *config.Config) {
+	// LIMITS_OVERCOMMIT
+	rule := cfg.Rules.Limits
+	if !rule.Enabled || rule.MaxOvercommit <= 0 {
+		return
+	}
+	t := ComputeLimitTotals(report)
+	limitsFacts := func() map[string]interface{} {
+		return map[string]interface{}{
+			"running_containers":   t.Running,
+			"memory_limit_bytes":   t.MemoryLimitBytes,
+			"host_memory_bytes":    t.HostMemoryBytes,
+			"memory_ratio":         t.MemoryRatio,
+			"without_memory_limit": t.WithoutMemoryLimit,
+			"cpu_limit_cores":      t.CPULimitCores,
+			"host_cpus":            t.HostCPUs,
+			"cpu_ratio":            t.CPURatio,
+			"without_cpu_limit":    t.WithoutCPULimit,
+			"max_overcommit":       rule.MaxOvercommit,
+		}
+	}
+
+	if t.MemoryRatio > rule.MaxOvercommit {
+		severity := "medium"
+		if t.MemoryRatio > rule.MaxOvercommit*2 {
+			severity = "high"
+		}
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "LIMITS_OVERCOMMIT",
+			Subject:     "host_memory",
+			Severity:    severity,
+			Category:    "limits",
+			Description: fmt.Sprintf("Memory limits of running containers add up to %s, %.0f%% of host memory (%s)", humanBytes(t.MemoryLimitBytes), t.MemoryRatio*100, humanBytes(t.HostMemoryBytes)),
+			Facts:       limitsFacts(),
+			Solutions: []string{
+				"Limits are not reservations: if containers grow towards their limits together the host runs out of memory and the kernel OOM killer picks victims.",
+				"Lower limits to observed usage plus headroom ('docker stats', or 'docker-doctor scan --sample 30s').",
+				"Move workloads to another host or add memory.",
+			},
+		})
+	}
+
+	if t.CPURatio > rule.MaxOvercommit {
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "LIMITS_OVERCOMMIT",
+			Subject:     "host_cpu",
+			Severity:    "low",
+			Category:    "limits",
+			Description: fmt.Sprintf("CPU limits of running containers add up to %.2f CPUs, %.0f%% of the host's %d CPUs", t.CPULimitCores, t.CPURatio*100, t.HostCPUs),
+			Facts:       limitsFacts(),
+			Solutions: []string{
+				"CPU overcommit is usually safe, but under contention containers get less than their limit and latency grows.",
+				"Use '--cpu-shares' to prioritise critical containers when they compete for CPU.",
+			},
+		})
+	}
+}
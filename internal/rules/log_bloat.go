@@ -37,6 +37,9 @@ func checkLogBloat(report *types.Report, cfg *config.Config) {
 						"Consider using external logging solutions (e.g., ELK stack, Fluentd).",
 						"Monitor application logging levels to reduce verbosity.",
 					},
+					Actions: []types.Action{
+						{Type: "log_truncate", Target: container.ID, Risk: "planned", Params: map[string]string{"mode": "truncate"}},
+					},
 				})
 			}
 		}
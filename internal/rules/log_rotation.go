@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// LogPolicy is the effective log retention of a container.
+type LogPolicy struct {
+	Driver     string
+	MaxSize    string // empty when json-file logs are unbounded
+	MaxFile    string
+	Bounded    bool   // log files on the host cannot grow without limit
+	Measurable bool   // docker-doctor reads the log size from the host (json-file only)
+	Summary    string // human readable, e.g. "10m x 3 files"
+}
+
+// ContainerLogPolicy derives the log policy from a container's log configuration.
+func ContainerLogPolicy(lc *types.LogConfig) LogPolicy {
+	p := LogPolicy{Driver: lc.Driver, MaxSize: lc.Opts["max-size"], MaxFile: lc.Opts["max-file"]}
+	switch lc.Driver {
+	case "json-file", "":
+		p.Driver = "json-file"
+		p.Measurable = true
+		if p.MaxSize == "-1" {
+			p.MaxSize = ""
+		}
+		if p.MaxSize == "" {
+			p.Summary = "unbounded"
+			break
+		}
+		p.Bounded = true
+		p.Summary = fmt.Sprintf("%s x %s files", p.MaxSize, fallbackString(p.MaxFile, "1"))
+	case "local":
+		// The local driver rotates by default: 20m per file, 5 files.
+		p.Bounded = true
+		p.Summary = fmt.Sprintf("%s x %s files", fallbackString(p.MaxSize, "20m"), fallbackString(p.MaxFile, "5"))
+		if p.MaxSize == "" && p.MaxFile == "" {
+			p.Summary += " (defaults)"
+		}
+	case "none":
+		p.Bounded = true
+		p.Measurable = true
+		p.Summary = "no logs kept"
+	default:
+		p.Summary = "shipped by the " + lc.Driver + " driver; size not measured"
+	}
+	return p
+}
+
+func checkLogRotation(report *types.Report, cfg *config.Config) {
+	// LOG_ROTATION_MISSING
+	rule := cfg.Rules.LogRotation
+	if !rule.Enabled {
+		return
+	}
+
+	unmeasured := map[string][]string{}
+	for _, c := range report.Containers.List {
+		if c.LogConfig == nil {
+			continue
+		}
+		name := strings.TrimPrefix(c.Name, "/")
+		p := ContainerLogPolicy(c.LogConfig)
+		if containsString(rule.IgnoreDrivers, p.Driver) {
+			continue
+		}
+		if !p.Bounded && !p.Measurable {
+			unmeasured[p.Driver] = append(unmeasured[p.Driver], name)
+			continue
+		}
+		if p.Driver != "json-file" || (p.MaxSize != "" && p.MaxFile != "") {
+			continue
+		}
+
+		severity := "low"
+		desc := fmt.Sprintf("Container %s (%s) logs with json-file and max-size but no max-file; only one file is kept and all history is lost at each rotation", name, c.ID)
+		if p.MaxSize == "" {
+			severity = "medium"
+			desc = fmt.Sprintf("Container %s (%s) logs with json-file without max-size; its log grows until the disk is full (currently %s)", name, c.ID, humanBytes(c.LogSize))
+		}
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "LOG_ROTATION_MISSING",
+			Subject:     "container=" + c.ID,
+			Severity:    severity,
+			Category:    "log_bloat",
+			Description: desc,
+			Facts: map[string]interface{}{
+				"container_id":   c.ID,
+				"container_name": name,
+				"log_driver":     p.Driver,
+				"log_opts":       c.LogConfig.Opts,
+				"log_policy":     p.Summary,
+				"log_size":       c.LogSize,
+			},
+			Solutions: []string{
+				"Recreate the container with rotation: 'docker run --log-opt max-size=10m --log-opt max-file=3 ...'; log options of an existing container cannot be changed.",
+				"In docker-compose.yml: 'logging: {driver: json-file, options: {max-size: \"10m\", max-file: \"3\"}}', then 'docker compose up -d'.",
+				"Set daemon-wide defaults in daemon.json (\"log-opts\": {\"max-size\": \"10m\", \"max-file\": \"3\"}) or use the 'local' driver, which rotates by default; see DAEMON_LOG_ROTATION_MISSING.",
+			},
+		})
+	}
+
+	drivers := make([]string, 0, len(unmeasured))
+	for d := range unmeasured {
+		drivers = append(drivers, d)
+	}
+	sort.Strings(drivers)
+	for _, d := range drivers {
+		names := unmeasured[d]
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "LOG_ROTATION_MISSING",
+			Subject:     "log_driver=" + d,
+			Severity:    "low",
+			Category:    "log_bloat",
+			Description: fmt.Sprintf("%d container(s) log with the %s driver, whose storage and retention docker-doctor cannot measure; LOG_BLOAT does not cover them", len(names), d),
+			Facts: map[string]interface{}{
+				"log_driver": d,
+				"containers": names,
+			},
+			Solutions: []string{
+				fmt.Sprintf("Check retention where the %s driver stores logs (e.g. journald's SystemMaxUse, syslog's logrotate, or the remote log service).", d),
+				"Add the driver to rules.log_rotation.ignore_drivers once its retention is managed elsewhere.",
+			},
+		})
+	}
+}
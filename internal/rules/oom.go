@@ -12,18 +12,32 @@ func checkOOM(report *types.Report, cfg *config.Config) {
 	if cfg.Rules.OOM.Enabled {
 		for _, container := range report.Containers.List {
 			if container.OOMKilled {
+				cause, note := oomAttribution(report, cfg, container)
+				factsMap := map[string]interface{}{
+					"container_id":     container.ID,
+					"container_name":   container.Name,
+					"status":           container.Status,
+					"oom_likely_cause": cause,
+				}
+				if container.Limits != nil {
+					factsMap["memory_limit"] = container.Limits.Memory
+				}
+				if res := report.Host.Resources; res != nil {
+					factsMap["host_memory_used_percent"] = hostMemoryUsedPercent(res)
+					factsMap["host_oom_kills"] = res.OOMKills
+					if res.PSIMemory != nil {
+						factsMap["host_psi_memory_some_avg60"] = res.PSIMemory.SomeAvg60
+					}
+				}
 				report.Issues = append(report.Issues, types.Issue{
 					RuleID:      "OOM_KILLED",
 					Subject:     "container=" + container.ID,
 					Severity:    "high",
 					Category:    "oom",
 					Description: fmt.Sprintf("Container %s (%s) was killed due to out-of-memory condition", container.Name, container.ID),
-					Facts: map[string]interface{}{
-						"container_id":   container.ID,
-						"container_name": container.Name,
-						"status":         container.Status,
-					},
+					Facts:       factsMap,
 					Solutions: []string{
+						note,
 						fmt.Sprintf("Check logs: 'docker logs %s'", container.ID),
 						"Increase memory limit: 'docker update --memory <limit> " + container.ID + "'",
 						"Optimize application memory usage.",
@@ -35,4 +49,23 @@ func checkOOM(report *types.Report, cfg *config.Config) {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+
+// oomAttribution tells an OOM kill caused by the container's own memory limit apart from
+// one caused by host-level memory pressure (the global OOM killer).
+func oomAttribution(report *types.Report, cfg *config.Config, container types.ContainerInfo) (cause, note string) {
+	pressured := hostMemoryPressured(report.Host.Resources, cfg.Rules.HostResources)
+	switch {
+	case container.Limits != nil && container.Limits.Memory <= 0:
+		now := "no host memory pressure is measured now, so it may have been a short spike"
+		if pressured {
+			now = "host memory is still under pressure (see HOST_MEMORY_PRESSURE)"
+		}
+		return "host_memory", fmt.Sprintf("The container has no memory limit, so this was likely a host-level OOM kill; %s. Confirm with 'dmesg | grep -i oom' or 'journalctl -k | grep -i oom'.", now)
+	case container.Limits != nil && !pressured:
+		return "container_limit", fmt.Sprintf("Host memory is not under pressure; the container most likely hit its own %s memory limit.", humanBytes(uint64(container.Limits.Memory)))
+	case pressured:
+		return "undetermined", "Host memory is under pressure as well; the kill may come from the container limit or from the host OOM killer ('dmesg | grep -i oom')."
+	}
+	return "undetermined", "Check 'dmesg | grep -i oom' to see whether the kill came from the container memory cgroup or the host OOM killer."
+}
@@ -0,0 +1,157 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// portClaim is a host port a container holds (running) or will bind on start (stopped).
+type portClaim struct {
+	container types.ContainerInfo
+	running   bool
+	hostIP    string
+}
+
+// ssProtocolFlags are the `ss` flags selecting each published-port protocol.
+var ssProtocolFlags = map[string]string{"tcp": "t", "udp": "u", "sctp": "S"}
+
+// ssCommand returns an `ss` invocation showing the process listening on port.
+func ssCommand(proto string, port int) string {
+	return fmt.Sprintf("sudo ss -lnp%s \"sport = :%d\"", ssProtocolFlags[proto], port)
+}
+
+func isWildcardAddr(addr string) bool {
+	return addr == "" || addr == "0.0.0.0" || addr == "::"
+}
+
+// addrsOverlap reports whether two bind addresses compete for the same port. A wildcard
+// address conflicts with every other address.
+func addrsOverlap(a, b string) bool {
+	return isWildcardAddr(a) || isWildcardAddr(b) || a == b
+}
+
+func checkPortConflict(report *types.Report, cfg *config.Config) {
+	// PORT_CONFLICT
+	if !cfg.Rules.PortConflict.Enabled {
+		return
+	}
+
+	claims := map[string][]portClaim{}
+	for _, c := range report.Containers.List {
+		running := isRunning(c)
+		bindings := c.ConfiguredPorts
+		if running && len(c.Ports) > 0 {
+			bindings = c.Ports
+		}
+		for _, p := range bindings {
+			key := fmt.Sprintf("%d/%s", p.HostPort, p.Protocol)
+			claims[key] = append(claims[key], portClaim{container: c, running: running, hostIP: p.HostIP})
+		}
+	}
+	listeners := map[string][]types.Listener{}
+	for _, l := range report.Host.Listeners {
+		key := fmt.Sprintf("%d/%s", l.Port, l.Protocol)
+		listeners[key] = append(listeners[key], l)
+	}
+
+	keys := make([]string, 0, len(claims))
+	for k := range claims {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		cs := claims[key]
+		conflicting := map[string]portClaim{}
+		anyRunning := false
+		for i, a := range cs {
+			anyRunning = anyRunning || a.running
+			for _, b := range cs[i+1:] {
+				if a.container.ID != b.container.ID && addrsOverlap(a.hostIP, b.hostIP) {
+					conflicting[a.container.ID] = a
+					conflicting[b.container.ID] = b
+				}
+			}
+		}
+
+		// With a running owner, a host listener on the port is normally docker-proxy itself.
+		var foreign []string
+		if !anyRunning {
+			for _, l := range listeners[key] {
+				for _, c := range cs {
+					if addrsOverlap(l.Address, c.hostIP) {
+						foreign = append(foreign, l.Address)
+						conflicting[c.container.ID] = c
+						break
+					}
+				}
+			}
+		}
+		if len(conflicting) == 0 {
+			continue
+		}
+
+		var names, runningNames, stoppedNames []string
+		for _, c := range conflicting {
+			name := strings.TrimPrefix(c.container.Name, "/")
+			names = append(names, name)
+			if c.running {
+				runningNames = append(runningNames, name)
+			} else {
+				stoppedNames = append(stoppedNames, name)
+			}
+		}
+		sort.Strings(names)
+		sort.Strings(runningNames)
+		sort.Strings(stoppedNames)
+
+		var description string
+		if len(foreign) > 0 && len(names) == 1 {
+			description = fmt.Sprintf("Host port %s is claimed by container %s but is already in use by a non-Docker process", key, names[0])
+		} else {
+			description = fmt.Sprintf("Host port %s is claimed by %d containers: %s", key, len(names), strings.Join(names, ", "))
+			if len(foreign) > 0 {
+				description += "; a non-Docker process is also listening on it"
+			}
+		}
+		if len(stoppedNames) > 0 {
+			description += fmt.Sprintf(". Starting %s will fail with 'port is already allocated'", strings.Join(stoppedNames, ", "))
+		}
+
+		portStr, proto, _ := strings.Cut(key, "/")
+		port, _ := strconv.Atoi(portStr)
+		solutions := []string{
+			"Give each container its own host port, or bind them to different host addresses ('-p <ip>:<port>:<container_port>').",
+			"Remove containers that are not used anymore: 'docker rm <container>'.",
+		}
+		if len(foreign) > 0 {
+			solutions = append(solutions, fmt.Sprintf("Find the process holding the port: '%s'", ssCommand(proto, port)))
+		}
+
+		facts := map[string]interface{}{
+			"host_port":          port,
+			"protocol":           proto,
+			"containers":         names,
+			"running_containers": runningNames,
+			"stopped_containers": stoppedNames,
+		}
+		if len(foreign) > 0 {
+			facts["host_listener_addresses"] = foreign
+		}
+
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "PORT_CONFLICT",
+			Subject:     "port=" + key,
+			Severity:    "medium",
+			Category:    "networking",
+			Description: description,
+			Facts:       facts,
+			Solutions:   solutions,
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// sensitivePorts are well-known ports of services that should not be reachable from
+// every interface: databases, caches, search engines, brokers and the Docker API.
+var sensitivePorts = map[int]string{
+	1433:  "Microsoft SQL Server",
+	1521:  "Oracle Database",
+	2181:  "ZooKeeper",
+	2375:  "Docker API (plain HTTP)",
+	2376:  "Docker API (TLS)",
+	2379:  "etcd",
+	3306:  "MySQL/MariaDB",
+	5432:  "PostgreSQL",
+	5672:  "RabbitMQ",
+	5984:  "CouchDB",
+	6379:  "Redis",
+	8086:  "InfluxDB",
+	9042:  "Cassandra",
+	9092:  "Kafka",
+	9200:  "Elasticsearch",
+	9300:  "Elasticsearch transport",
+	11211: "Memcached",
+	15672: "RabbitMQ management",
+	27017: "MongoDB",
+}
+
+// SensitivePortService returns the service name of a well-known sensitive port.
+func SensitivePortService(port int) (string, bool) {
+	name, ok := sensitivePorts[port]
+	return name, ok
+}
+
+// IsPublicBinding reports whether a port is published on all host interfaces.
+func IsPublicBinding(p types.PortBinding) bool {
+	return p.HostIP == "" || p.HostIP == "0.0.0.0" || p.HostIP == "::"
+}
+
+func portAllowed(allow []config.PortAllow, container string, p types.PortBinding) bool {
+	for _, a := range allow {
+		if a.Port != p.HostPort || (a.Protocol != "" && a.Protocol != p.Protocol) {
+			continue
+		}
+		if a.Container == "" || a.Container == container {
+			return true
+		}
+	}
+	return false
+}
+
+func checkPorts(report *types.Report, cfg *config.Config) {
+	// PORT_EXPOSED_PUBLIC
+	rule := cfg.Rules.Ports
+	if !rule.Enabled {
+		return
+	}
+	var allow []config.PortAllow
+	for _, entry := range rule.Allow {
+		// Entries were validated when the config was loaded.
+		if a, err := config.ParsePortAllow(entry); err == nil {
+			allow = append(allow, a)
+		}
+	}
+
+	for _, container := range report.Containers.List {
+		name := strings.TrimPrefix(container.Name, "/")
+		seen := map[string]bool{}
+		var exposed []map[string]interface{}
+		var labels, sensitive []string
+		for _, p := range container.Ports {
+			if !IsPublicBinding(p) || portAllowed(allow, name, p) {
+				continue
+			}
+			// Docker publishes on 0.0.0.0 and :: separately; report each host port once.
+			key := fmt.Sprintf("%d/%s", p.HostPort, p.Protocol)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			entry := map[string]interface{}{
+				"host_ip":        p.HostIP,
+				"host_port":      p.HostPort,
+				"container_port": p.ContainerPort,
+				"protocol":       p.Protocol,
+			}
+			label := fmt.Sprintf("%d->%d/%s", p.HostPort, p.ContainerPort, p.Protocol)
+			service, ok := SensitivePortService(p.ContainerPort)
+			if !ok {
+				service, ok = SensitivePortService(p.HostPort)
+			}
+			if ok {
+				entry["service"] = service
+				label += " (" + service + ")"
+				sensitive = append(sensitive, service)
+			}
+			exposed = append(exposed, entry)
+			labels = append(labels, label)
+		}
+		if len(exposed) == 0 {
+			continue
+		}
+
+		severity := "low"
+		if len(sensitive) > 0 {
+			severity = "high"
+		}
+		solutions := []string{
+			"Bind to loopback when the port is only used locally or through a reverse proxy: '-p 127.0.0.1:<host>:<container>' (compose: '\"127.0.0.1:<host>:<container>\"').",
+			"Docker writes its own iptables rules, so published ports bypass host firewalls such as ufw or firewalld.",
+			"Reach internal services over a user-defined Docker network instead of publishing them.",
+		}
+		if len(sensitive) > 0 {
+			solutions = append(solutions, "Databases, caches and the Docker API are common targets of internet-wide scans; never publish them on all interfaces without authentication and TLS.")
+		}
+		solutions = append(solutions, fmt.Sprintf("If the exposure is intended, add the port to rules.ports.allow (e.g. \"%s:%v\").", name, exposed[0]["host_port"]))
+
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "PORT_EXPOSED_PUBLIC",
+			Subject:     "container=" + container.ID,
+			Severity:    severity,
+			Category:    "security",
+			Description: fmt.Sprintf("Container %s (%s) publishes %d port(s) on all interfaces: %s", container.Name, container.ID, len(exposed), strings.Join(labels, ", ")),
+			Facts: map[string]interface{}{
+				"container_id":       container.ID,
+				"container_name":     container.Name,
+				"ports":              exposed,
+				"sensitive_services": sensitive,
+			},
+			Solutions: solutions,
+		})
+	}
+}
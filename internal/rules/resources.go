@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func checkResources(report *types.Report, cfg *config.Config) {
+	// CPU_THROTTLED / MEMORY_NEAR_LIMIT / PIDS_NEAR_LIMIT (need scan.sample)
+	rule := cfg.Rules.Resources
+	if !rule.Enabled {
+		return
+	}
+	for _, container := range report.Containers.List {
+		s := container.Stats
+		if s == nil {
+			continue
+		}
+		window := fmt.Sprintf("%.0fs", s.DurationSeconds)
+
+		if rule.ThrottledRatio > 0 && s.Periods > 0 && s.ThrottledRatio >= rule.ThrottledRatio {
+			severity := "medium"
+			if s.ThrottledRatio >= 0.5 || s.ThrottledRatio >= rule.ThrottledRatio*2 {
+				severity = "high"
+			}
+			limit := "no CPU limit (throttled by CPU shares or the parent cgroup)"
+			if s.CPULimitCores > 0 {
+				limit = fmt.Sprintf("a limit of %.2f CPUs", s.CPULimitCores)
+			}
+			report.Issues = append(report.Issues, types.Issue{
+				RuleID:      "CPU_THROTTLED",
+				Subject:     "container=" + container.ID,
+				Severity:    severity,
+				Category:    "resources",
+				Description: fmt.Sprintf("Container %s (%s) was CPU throttled in %.0f%% of scheduling periods over %s with %s", container.Name, container.ID, s.ThrottledRatio*100, window, limit),
+				Facts: map[string]interface{}{
+					"container_id":      container.ID,
+					"container_name":    container.Name,
+					"throttled_ratio":   s.ThrottledRatio,
+					"throttled_periods": s.ThrottledPeriods,
+					"periods":           s.Periods,
+					"cpu_percent":       s.CPUPercent,
+					"cpu_percent_max":   s.CPUPercentMax,
+					"cpu_limit_cores":   s.CPULimitCores,
+					"sample_seconds":    s.DurationSeconds,
+					"threshold":         rule.ThrottledRatio,
+				},
+				Solutions: []string{
+					fmt.Sprintf("Raise the CPU limit: 'docker update --cpus <n> %s'", container.ID),
+					"Throttling adds latency even when average CPU usage looks low; check request latency of the service.",
+					"Reduce worker threads/processes to fit the quota, or spread load over more replicas.",
+				},
+			})
+		}
+
+		if rule.MemoryPercent > 0 && s.MemoryLimitSet && s.MemoryLimit > 0 {
+			percent := float64(s.MemoryUsage) / float64(s.MemoryLimit) * 100
+			if percent >= float64(rule.MemoryPercent) {
+				severity := "medium"
+				if percent >= 98 {
+					severity = "high"
+				}
+				report.Issues = append(report.Issues, types.Issue{
+					RuleID:      "MEMORY_NEAR_LIMIT",
+					Subject:     "container=" + container.ID,
+					Severity:    severity,
+					Category:    "resources",
+					Description: fmt.Sprintf("Container %s (%s) used up to %s of its %s memory limit (%.1f%%) over %s", container.Name, container.ID, humanBytes(s.MemoryUsage), humanBytes(s.MemoryLimit), percent, window),
+					Facts: map[string]interface{}{
+						"container_id":   container.ID,
+						"container_name": container.Name,
+						"memory_usage":   s.MemoryUsage,
+						"memory_limit":   s.MemoryLimit,
+						"used_percent":   percent,
+						"sample_seconds": s.DurationSeconds,
+						"threshold":      rule.MemoryPercent,
+					},
+					Solutions: []string{
+						fmt.Sprintf("Raise the memory limit: 'docker update --memory <size> --memory-swap <size> %s'", container.ID),
+						"The kernel OOM-kills the container when usage reaches the limit; check for leaks or unbounded caches.",
+						"Usage excludes inactive page cache, the same figure 'docker stats' shows.",
+					},
+				})
+			}
+		}
+
+		if rule.PidsPercent > 0 && s.PidsLimit > 0 {
+			percent := float64(s.PidsCurrent) / float64(s.PidsLimit) * 100
+			if percent >= float64(rule.PidsPercent) {
+				severity := "medium"
+				if s.PidsCurrent >= s.PidsLimit {
+					severity = "high"
+				}
+				report.Issues = append(report.Issues, types.Issue{
+					RuleID:      "PIDS_NEAR_LIMIT",
+					Subject:     "container=" + container.ID,
+					Severity:    severity,
+					Category:    "resources",
+					Description: fmt.Sprintf("Container %s (%s) reached %d of its %d PIDs limit (%.0f%%)", container.Name, container.ID, s.PidsCurrent, s.PidsLimit, percent),
+					Facts: map[string]interface{}{
+						"container_id":   container.ID,
+						"container_name": container.Name,
+						"pids_current":   s.PidsCurrent,
+						"pids_limit":     s.PidsLimit,
+						"used_percent":   percent,
+						"sample_seconds": s.DurationSeconds,
+						"threshold":      rule.PidsPercent,
+					},
+					Solutions: []string{
+						fmt.Sprintf("Inspect processes/threads: 'docker top %s'", container.ID),
+						"At the limit fork() and thread creation fail; look for leaked child processes or unbounded thread pools.",
+						fmt.Sprintf("Raise the limit if the load is expected: 'docker update --pids-limit <n> %s'", container.ID),
+					},
+				})
+			}
+		}
+	}
+}
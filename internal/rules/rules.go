@@ -79,11 +79,22 @@ func Evaluate(report *types.Report, cfg *config.Config, df *facts.DockerSystemDf
 
 	// Run all rule checks
 	checkDiskUsage(report, cfg)
+	checkInodeUsage(report, cfg)
 	checkStorageBloat(report, cfg, df)
-	checkRestarts(report, cfg)
-	checkOOM(report, cfg)
-	checkHealthcheck(report, cfg)
-	checkLogBloat(report, cfg)
+	checkBuildCache(report, cfg, df)
+	checkImages(report, cfg, df)
+	for _, check := range containerChecks {
+		check(report, cfg)
+	}
+	checkLimitsOvercommit(report, cfg)
+	checkHostResources(report, cfg)
+	checkPortConflict(report, cfg)
+	checkDaemonConfig(report, cfg)
+	checkDaemonWarnings(report, cfg)
+	checkStorageDriver(report, cfg)
+	checkSysctl(report, cfg)
+	checkCgroup(report, cfg)
+	checkEngineVersion(report, cfg)
 
 	// Deterministic ordering for diff-friendly output
 	severityRank := func(s string) int {
@@ -122,3 +133,37 @@ func Evaluate(report *types.Report, cfg *config.Config, df *facts.DockerSystemDf
 	})
 }
 
+
+// containerChecks are the rules that judge each container on its own data. Evaluate runs
+// them for every container and EvaluateContainer for one; rules that compare containers
+// (PORT_CONFLICT, LIMITS_OVERCOMMIT) or need host data belong in Evaluate only.
+var containerChecks = []func(*types.Report, *config.Config){
+	checkRestarts,
+	checkOOM,
+	checkHealthcheck,
+	checkLogBloat,
+	checkWritableLayer,
+	checkResources,
+	checkContainerLimits,
+	checkSecurity,
+	checkSecretEnv,
+	checkPorts,
+	checkLogRotation,
+}
+
+// EvaluateContainer runs only the per-container rules against a single container and
+// returns the resulting issues. Watch mode uses it to re-evaluate a container after an
+// event without rescanning the whole host.
+func EvaluateContainer(container types.ContainerInfo, cfg *config.Config) []types.Issue {
+	if cfg == nil {
+		return nil
+	}
+	report := &types.Report{
+		Containers: types.Containers{Count: 1, List: []types.ContainerInfo{container}},
+		Issues:     []types.Issue{},
+	}
+	for _, check := range containerChecks {
+		check(report, cfg)
+	}
+	return report.Issues
+}
@@ -1,6 +1,10 @@
 package rules
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -199,3 +203,854 @@ func TestEvaluate_DeterministicIssueOrdering(t *testing.T) {
 	}
 }
 
+
+func TestCheckBuildCache_Breakdown(t *testing.T) {
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-10 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+	cfg := &config.Config{Rules: config.Rules{BuildCache: config.BuildCacheRule{Enabled: true, SizeThreshold: 500, IdleDays: 7}}}
+	df := &facts.DockerSystemDfSummary{BuildCache: []facts.DfBuildCache{
+		{ID: "a", Type: "regular", Size: 300, LastUsedAt: &old},
+		{ID: "b", Type: "exec.cachemount", Size: 200, Shared: true, LastUsedAt: &old},
+		{ID: "c", Type: "source.local", Size: 100, LastUsedAt: &recent},
+		{ID: "d", Type: "regular", Size: 50, InUse: true, CreatedAt: old},
+	}}
+
+	report := &types.Report{}
+	checkBuildCacheAt(report, cfg, df, now)
+	if len(report.Issues) != 1 || report.Issues[0].RuleID != "BUILD_CACHE_BLOAT" {
+		t.Fatalf("expected BUILD_CACHE_BLOAT, got %+v", report.Issues)
+	}
+	is := report.Issues[0]
+	if is.Severity != "medium" {
+		t.Fatalf("expected medium severity, got %s", is.Severity)
+	}
+	for k, want := range map[string]uint64{"total_size": 650, "idle_bytes": 500, "reclaimable_bytes": 300, "in_use_bytes": 50, "shared_bytes": 200} {
+		if is.Facts[k] != want {
+			t.Fatalf("fact %s = %v, want %d", k, is.Facts[k], want)
+		}
+	}
+	if byType := is.Facts["bytes_by_type"].(map[string]uint64); byType["regular"] != 350 || byType["source.local"] != 100 {
+		t.Fatalf("unexpected type breakdown %v", byType)
+	}
+	if byAge := is.Facts["bytes_by_age"].(map[string]uint64); byAge["lt_1d"] != 100 || byAge["7d_30d"] != 550 {
+		t.Fatalf("unexpected age breakdown %v", byAge)
+	}
+	if len(is.Actions) != 1 || is.Actions[0].Params["until"] != "168h" {
+		t.Fatalf("expected builder_prune until=168h, got %+v", is.Actions)
+	}
+
+	report = &types.Report{}
+	cfg.Rules.BuildCache.SizeThreshold = 1000
+	checkBuildCacheAt(report, cfg, df, now)
+	if len(report.Issues) != 0 {
+		t.Fatalf("expected no issue below threshold, got %+v", report.Issues)
+	}
+
+	// Without idle_days every unused record counts; frontend records survive a plain prune.
+	df.BuildCache = append(df.BuildCache, facts.DfBuildCache{ID: "e", Type: "frontend", Size: 400, LastUsedAt: &old})
+	cfg.Rules.BuildCache = config.BuildCacheRule{Enabled: true, SizeThreshold: 500}
+	report = &types.Report{}
+	checkBuildCacheAt(report, cfg, df, now)
+	is = report.Issues[0]
+	if is.Facts["reclaimable_bytes"] != uint64(400) {
+		t.Fatalf("expected the regular records only, got %v", is.Facts["reclaimable_bytes"])
+	}
+	if !strings.HasPrefix(is.Solutions[0], "Prune build cache not in use (frees about") || strings.Contains(is.Description, "0+ days") {
+		t.Fatalf("unexpected idle_days=0 wording: %q / %q", is.Description, is.Solutions[0])
+	}
+}
+
+func TestCheckImages_DanglingUnusedAndPileup(t *testing.T) {
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	days := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+	cfg := &config.Config{Rules: config.Rules{Images: config.ImagesRule{Enabled: true, UnusedDays: 30, MaxVersions: 2}}}
+	report := &types.Report{
+		Images: types.Images{List: []types.ImageInfo{
+			{ID: "sha256:dangling", Size: 100, RepoTags: []string{"<none>:<none>"}, Created: days(5), Containers: -1, SharedSize: -1},
+			{ID: "sha256:parent", Size: 50, Created: days(90), Containers: -1, SharedSize: -1},
+			{ID: "sha256:child", Size: 80, ParentID: "sha256:parent", RepoTags: []string{"tool:1"}, Created: days(1), Containers: -1, SharedSize: -1},
+			{ID: "sha256:app1", Size: 1000, RepoTags: []string{"registry:5000/app:1"}, Created: days(60), Containers: -1, SharedSize: -1},
+			{ID: "sha256:app2", Size: 1000, RepoTags: []string{"registry:5000/app:2"}, Created: days(40), Containers: -1, SharedSize: -1},
+			{ID: "sha256:app3", Size: 1000, RepoTags: []string{"registry:5000/app:3", "registry:5000/app:latest"}, Created: days(1), Containers: -1, SharedSize: -1},
+		}},
+		Containers: types.Containers{List: []types.ContainerInfo{{ID: "c1", Name: "/app", ImageID: "sha256:app3"}}},
+	}
+	// /system/df reports shared sizes the image list lacks on older API versions.
+	df := &facts.DockerSystemDfSummary{Images: []facts.DfImage{
+		{ID: "sha256:app1", SharedSize: 600, Containers: 0},
+		{ID: "sha256:app2", SharedSize: 600, Containers: 0},
+	}}
+
+	checkImagesAt(report, cfg, df, now)
+	byRule := map[string]types.Issue{}
+	for _, is := range report.Issues {
+		byRule[is.RuleID] = is
+	}
+	if len(byRule) != 3 {
+		t.Fatalf("expected 3 image rules, got %+v", report.Issues)
+	}
+	if is := byRule["IMAGE_DANGLING"]; is.Facts["dangling_images"] != 1 || is.Facts["reclaimable_bytes"] != uint64(100) {
+		t.Fatalf("unexpected IMAGE_DANGLING facts %v (intermediate parent must be skipped)", is.Facts)
+	}
+	if is := byRule["IMAGE_UNUSED"]; is.Facts["unused_images"] != 2 || is.Facts["created_more_than_days"] != 30 || is.Facts["reclaimable_bytes"] != uint64(800) {
+		t.Fatalf("unexpected IMAGE_UNUSED facts %v", is.Facts)
+	}
+	pile := byRule["IMAGE_VERSIONS_PILEUP"]
+	if pile.Subject != "repository=registry:5000/app" || pile.Facts["versions"] != 3 || pile.Facts["reclaimable_bytes"] != uint64(400) {
+		t.Fatalf("unexpected IMAGE_VERSIONS_PILEUP %+v", pile)
+	}
+	if len(pile.Actions) != 1 || pile.Actions[0].Target != "registry:5000/app:1" {
+		t.Fatalf("expected removal of the oldest version only, got %+v", pile.Actions)
+	}
+}
+
+func TestCheckWritableLayer(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{WritableLayer: config.WritableLayerRule{Enabled: true, SizeThreshold: 1000}}}
+	report := &types.Report{Containers: types.Containers{List: []types.ContainerInfo{
+		{ID: "small", Name: "/small", SizeRw: 500},
+		{ID: "big", Name: "/db", SizeRw: 3000, WritableTopPaths: []types.PathUsage{{Path: "/var/lib", Bytes: 2500, Changes: 3}}},
+	}}}
+
+	checkWritableLayer(report, cfg)
+	if len(report.Issues) != 1 || report.Issues[0].Subject != "container=big" || report.Issues[0].Severity != "high" {
+		t.Fatalf("expected one high CONTAINER_WRITABLE_LAYER_LARGE for big, got %+v", report.Issues)
+	}
+	if top, ok := report.Issues[0].Facts["top_paths"].([]string); !ok || len(top) != 1 {
+		t.Fatalf("expected top_paths evidence, got %v", report.Issues[0].Facts)
+	}
+}
+
+func TestCheckResources(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{Resources: config.ResourcesRule{Enabled: true, ThrottledRatio: 0.2, MemoryPercent: 90, PidsPercent: 90}}}
+	report := &types.Report{Containers: types.Containers{List: []types.ContainerInfo{
+		{ID: "unsampled", Name: "/idle"},
+		{ID: "cpu", Name: "/api", Stats: &types.ResourceSample{DurationSeconds: 30, Periods: 300, ThrottledPeriods: 90, ThrottledRatio: 0.3, CPULimitCores: 0.5}},
+		{ID: "mem", Name: "/db", Stats: &types.ResourceSample{DurationSeconds: 30, MemoryUsage: 990, MemoryLimit: 1000, MemoryLimitSet: true}},
+		{ID: "hostmem", Name: "/batch", Stats: &types.ResourceSample{DurationSeconds: 30, MemoryUsage: 990, MemoryLimit: 1000}},
+		{ID: "pids", Name: "/worker", Stats: &types.ResourceSample{DurationSeconds: 30, PidsCurrent: 95, PidsLimit: 100}},
+	}}}
+
+	checkResources(report, cfg)
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		got[is.RuleID+" "+is.Subject] = is.Severity
+	}
+	want := map[string]string{
+		"CPU_THROTTLED container=cpu":     "medium",
+		"MEMORY_NEAR_LIMIT container=mem": "high",
+		"PIDS_NEAR_LIMIT container=pids":  "medium",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for k, sev := range want {
+		if got[k] != sev {
+			t.Fatalf("expected %s with severity %s, got %v", k, sev, got)
+		}
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{Limits: config.LimitsRule{Enabled: true, MaxOvercommit: 1.0}}}
+	report := &types.Report{
+		Docker: types.DockerInfo{MemTotal: 1000, NCPU: 2},
+		Containers: types.Containers{List: []types.ContainerInfo{
+			{ID: "open", Name: "/open", Status: "Up 1 hour", Limits: &types.ResourceLimits{}},
+			{ID: "swap", Name: "/swap", Status: "Up 1 hour", Limits: &types.ResourceLimits{Memory: 800, MemorySwap: -1, PidsLimit: 100, NanoCPUs: 1500000000}},
+			{ID: "tight", Name: "/tight", Status: "Up 1 hour", Limits: &types.ResourceLimits{Memory: 700, MemorySwap: 700, PidsLimit: 100, CPUQuota: 50000, CPUPeriod: 100000}},
+			{ID: "stopped", Name: "/stopped", Status: "Exited (0)", Limits: &types.ResourceLimits{}},
+		}},
+	}
+
+	checkContainerLimits(report, cfg)
+	checkLimitsOvercommit(report, cfg)
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		got[is.RuleID+" "+is.Subject] = is.Severity
+	}
+	want := map[string]string{
+		"CONTAINER_NO_MEMORY_LIMIT container=open": "low",
+		"CONTAINER_NO_PIDS_LIMIT container=open":   "low",
+		"CONTAINER_SWAP_UNLIMITED container=swap":  "medium",
+		"LIMITS_OVERCOMMIT host_memory":            "medium",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for k, sev := range want {
+		if got[k] != sev {
+			t.Fatalf("expected %s with severity %s, got %v", k, sev, got)
+		}
+	}
+
+	totals := ComputeLimitTotals(report)
+	if totals.Running != 3 || totals.MemoryLimitBytes != 1500 || totals.CPULimitCores != 2 || totals.CPURatio != 1 || totals.WithoutMemoryLimit != 1 {
+		t.Fatalf("unexpected totals %+v", totals)
+	}
+}
+
+func TestEvaluateContainer_RunsPerContainerRules(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{
+		Limits:      config.LimitsRule{Enabled: true, MaxOvercommit: 1.0},
+		Security:    config.SecurityRule{Enabled: true},
+		SecretEnv:   config.SecretEnvRule{Enabled: true},
+		Ports:       config.PortsRule{Enabled: true},
+		LogRotation: config.LogRotationRule{Enabled: true},
+	}}
+	container := types.ContainerInfo{
+		ID:           "abc",
+		Name:         "/api",
+		Status:       "Up 1 hour",
+		Limits:       &types.ResourceLimits{PidsLimit: 100},
+		Security:     &types.SecurityInfo{Privileged: true, ReadonlyRootfs: true, User: "1000"},
+		SensitiveEnv: []types.SensitiveEnvVar{{Name: "DB_PASSWORD", Kind: "password", MatchedBy: "name"}},
+		Ports:        []types.PortBinding{{HostIP: "0.0.0.0", HostPort: 5432, ContainerPort: 5432, Protocol: "tcp"}},
+		LogConfig:    &types.LogConfig{Driver: "json-file"},
+	}
+
+	got := map[string]bool{}
+	for _, is := range EvaluateContainer(container, cfg) {
+		got[is.RuleID] = true
+	}
+	for _, id := range []string{"CONTAINER_NO_MEMORY_LIMIT", "CONTAINER_PRIVILEGED", "SECRET_IN_ENV", "PORT_EXPOSED_PUBLIC", "LOG_ROTATION_MISSING"} {
+		if !got[id] {
+			t.Fatalf("expected %s from EvaluateContainer, got %v", id, got)
+		}
+	}
+	if got["LIMITS_OVERCOMMIT"] {
+		t.Fatalf("host-level overcommit must not be evaluated per container, got %v", got)
+	}
+}
+
+func TestCheckHostResources(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{HostResources: config.HostResourcesRule{Enabled: true, MemoryPercent: 90, SwapPercent: 50, LoadPerCPU: 2, PSIThreshold: 10}}}
+	report := &types.Report{Host: types.HostInfo{Resources: &types.HostResources{
+		MemTotal: 1000, MemAvailable: 80,
+		SwapTotal: 100, SwapFree: 90,
+		Load5: 9, NCPU: 2,
+		PSIMemory: &types.Pressure{SomeAvg60: 2},
+	}}}
+
+	checkHostResources(report, cfg)
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		got[is.RuleID] = is.Severity
+	}
+	if len(got) != 2 || got["HOST_MEMORY_PRESSURE"] != "medium" || got["HOST_LOAD_HIGH"] != "high" {
+		t.Fatalf("expected medium HOST_MEMORY_PRESSURE and high HOST_LOAD_HIGH, got %v", got)
+	}
+}
+
+func TestCheckOOM_Attribution(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{OOM: config.OOMRule{Enabled: true}}}
+	report := &types.Report{
+		Host: types.HostInfo{Resources: &types.HostResources{MemTotal: 1000, MemAvailable: 500}},
+		Containers: types.Containers{List: []types.ContainerInfo{
+			{ID: "limited", OOMKilled: true, Limits: &types.ResourceLimits{Memory: 100}},
+			{ID: "unlimited", OOMKilled: true, Limits: &types.ResourceLimits{}},
+			{ID: "unknown", OOMKilled: true},
+		}},
+	}
+
+	checkOOM(report, cfg)
+	want := map[string]string{"container=limited": "container_limit", "container=unlimited": "host_memory", "container=unknown": "undetermined"}
+	if len(report.Issues) != len(want) {
+		t.Fatalf("expected %d OOM issues, got %d", len(want), len(report.Issues))
+	}
+	for _, is := range report.Issues {
+		if is.Facts["oom_likely_cause"] != want[is.Subject] {
+			t.Fatalf("%s: expected cause %s, got %v", is.Subject, want[is.Subject], is.Facts["oom_likely_cause"])
+		}
+		if is.Subject == "container=unlimited" && (!strings.Contains(is.Solutions[0], "likely a host-level OOM") || !strings.Contains(is.Solutions[0], "journalctl -k")) {
+			t.Fatalf("expected a hedged host-level OOM note pointing at the kernel log, got %q", is.Solutions[0])
+		}
+	}
+}
+
+func TestCheckInodeUsage(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{InodeUsage: config.InodeUsageRule{Enabled: true, Threshold: 80, CriticalThreshold: 95}}}
+	report := &types.Report{Host: types.HostInfo{DiskUsage: map[string]*types.DiskInfo{
+		"/":               {UsedPercent: 40, InodesUsed: 85, InodesTotal: 100, InodesUsedPercent: 85},
+		"/var/lib/docker": {UsedPercent: 30, InodesUsed: 97, InodesTotal: 100, InodesUsedPercent: 97},
+		"/data":           {UsedPercent: 99}, // no inode data (e.g. btrfs)
+	}}}
+
+	checkInodeUsage(report, cfg)
+	if len(report.Issues) != 2 {
+		t.Fatalf("expected 2 INODE_USAGE_HIGH issues, got %+v", report.Issues)
+	}
+	if report.Issues[0].Subject != "path=/" || report.Issues[0].Severity != "medium" {
+		t.Fatalf("unexpected issue for /: %+v", report.Issues[0])
+	}
+	if report.Issues[1].Subject != "path=/var/lib/docker" || report.Issues[1].Severity != "high" || len(report.Issues[1].Actions) != 1 {
+		t.Fatalf("unexpected issue for /var/lib/docker: %+v", report.Issues[1])
+	}
+}
+
+func TestCheckDiskUsage_PerPathThresholds(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{DiskUsage: config.DiskUsageRule{
+		Threshold: 80,
+		Critical:  95,
+		Paths: []config.DiskPath{
+			{Path: "/var/log", Warning: 60, Critical: 70},
+			{Path: "/srv/data", Warning: 90},
+		},
+	}}}
+	report := &types.Report{Host: types.HostInfo{DiskUsage: map[string]*types.DiskInfo{
+		"/":         {UsedPercent: 75, Paths: []string{"/var/log"}, MountPoint: "/", FSType: "ext4"},
+		"/srv/data": {UsedPercent: 85},
+		"/backup":   {UsedPercent: 96},
+	}}}
+
+	checkDiskUsage(report, cfg)
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		got[is.Subject] = is.Severity
+	}
+	want := map[string]string{"path=/": "high", "path=/backup": "high"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestCheckSecurity(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{Security: config.SecurityRule{Enabled: true, IgnoreContainers: []string{"trusted"}}}}
+	report := &types.Report{Containers: types.Containers{List: []types.ContainerInfo{
+		{
+			ID:   "abc123",
+			Name: "/risky",
+			Security: &types.SecurityInfo{
+				Privileged:  true,
+				CapAdd:      []string{"CAP_NET_ADMIN", "SYS_ADMIN", "CHOWN"},
+				NetworkMode: "host",
+				PidMode:     "host",
+				SecurityOpt: []string{"seccomp:unconfined", "apparmor=unconfined"},
+				User:        "0:0",
+			},
+			Mounts: []types.Mount{{Type: "bind", Source: "/var/run/docker.sock", Destination: "/var/run/docker.sock"}},
+		},
+		{
+			ID:       "def456",
+			Name:     "/hardened",
+			Security: &types.SecurityInfo{NetworkMode: "bridge", ReadonlyRootfs: true, User: "1000:1000"},
+		},
+		{
+			ID:       "ghi789",
+			Name:     "/trusted",
+			Security: &types.SecurityInfo{Privileged: true},
+		},
+	}}}
+
+	checkSecurity(report, cfg)
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		if is.Subject != "container=abc123" {
+			t.Fatalf("unexpected finding for %s: %s", is.Subject, is.RuleID)
+		}
+		if is.Category != "security" || len(is.References) != 1 || is.References[0].Kind != "cis" {
+			t.Fatalf("expected a security finding with a CIS reference, got %+v", is)
+		}
+		got[is.RuleID] = is.Severity
+	}
+	want := map[string]string{
+		"CONTAINER_PRIVILEGED":             "high",
+		"CONTAINER_DANGEROUS_CAPABILITIES": "high",
+		"CONTAINER_DOCKER_SOCKET_MOUNTED":  "high",
+		"CONTAINER_HOST_NETWORK":           "medium",
+		"CONTAINER_HOST_PID":               "medium",
+		"CONTAINER_SECCOMP_DISABLED":       "medium",
+		"CONTAINER_APPARMOR_DISABLED":      "medium",
+		"CONTAINER_ROOTFS_WRITABLE":        "low",
+		"CONTAINER_RUNS_AS_ROOT":           "low",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestCheckSecretEnv(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{SecretEnv: config.SecretEnvRule{Enabled: true, IgnoreNames: []string{"TOKEN_TTL"}}}}
+	report := &types.Report{Containers: types.Containers{List: []types.ContainerInfo{
+		{ID: "abc", Name: "/api", SensitiveEnv: []types.SensitiveEnvVar{
+			{Name: "DB_PASSWORD", Kind: "password", MatchedBy: "name", ValueHMAC: "0123456789abcdef"},
+			{Name: "TOKEN_TTL", Kind: "token", MatchedBy: "name", ValueHMAC: "fedcba9876543210"},
+		}},
+		{ID: "def", Name: "/worker", SensitiveEnv: []types.SensitiveEnvVar{
+			{Name: "TOKEN_TTL", Kind: "token", MatchedBy: "name", ValueHMAC: "fedcba9876543210"},
+		}},
+		{ID: "ghi", Name: "/ci", SensitiveEnv: []types.SensitiveEnvVar{
+			{Name: "UPSTREAM", Kind: "github_token", MatchedBy: "value", ValueHMAC: "aaaaaaaaaaaaaaaa"},
+		}},
+	}}}
+
+	checkSecretEnv(report, cfg)
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		got[is.Subject] = is.Severity
+	}
+	want := map[string]string{"container=abc": "medium", "container=ghi": "high"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	vars := report.Issues[0].Facts["variables"].([]map[string]interface{})
+	if len(vars) != 1 || vars[0]["name"] != "DB_PASSWORD" {
+		t.Fatalf("expected only DB_PASSWORD in evidence, got %v", vars)
+	}
+}
+
+func TestCheckPorts(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{Ports: config.PortsRule{Enabled: true, Allow: []string{"443", "web:8080"}}}}
+	report := &types.Report{Containers: types.Containers{List: []types.ContainerInfo{
+		{ID: "abc", Name: "/cache", Ports: []types.PortBinding{
+			{HostIP: "0.0.0.0", HostPort: 16379, ContainerPort: 6379, Protocol: "tcp"},
+			{HostIP: "::", HostPort: 16379, ContainerPort: 6379, Protocol: "tcp"},
+		}},
+		{ID: "def", Name: "/web", Ports: []types.PortBinding{
+			{HostIP: "0.0.0.0", HostPort: 443, ContainerPort: 443, Protocol: "tcp"},
+			{HostIP: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+			{HostIP: "127.0.0.1", HostPort: 5432, ContainerPort: 5432, Protocol: "tcp"},
+		}},
+		{ID: "ghi", Name: "/api", Ports: []types.PortBinding{
+			{HostIP: "0.0.0.0", HostPort: 8080, ContainerPort: 8080, Protocol: "tcp"},
+		}},
+	}}}
+
+	checkPorts(report, cfg)
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		got[is.Subject] = is.Severity
+	}
+	want := map[string]string{"container=abc": "high", "container=ghi": "low"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if ports := report.Issues[0].Facts["ports"].([]map[string]interface{}); len(ports) != 1 || ports[0]["service"] != "Redis" {
+		t.Fatalf("expected a single Redis port, got %v", ports)
+	}
+}
+
+func TestCheckPortConflict(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{PortConflict: config.PortConflictRule{Enabled: true}}}
+	report := &types.Report{
+		Host: types.HostInfo{Listeners: []types.Listener{
+			{Protocol: "tcp", Address: "0.0.0.0", Port: 8080}, // docker-proxy of web
+			{Protocol: "tcp", Address: "127.0.0.1", Port: 5432},
+		}},
+		Containers: types.Containers{List: []types.ContainerInfo{
+			{ID: "a", Name: "/web", Status: "Up 2 hours",
+				Ports:           []types.PortBinding{{HostIP: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}},
+				ConfiguredPorts: []types.PortBinding{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}}},
+			{ID: "b", Name: "/web-old", Status: "Exited (0) 3 days ago",
+				ConfiguredPorts: []types.PortBinding{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}}},
+			{ID: "c", Name: "/db", Status: "Exited (0) 1 day ago",
+				ConfiguredPorts: []types.PortBinding{{HostIP: "127.0.0.1", HostPort: 5432, ContainerPort: 5432, Protocol: "tcp"}}},
+			{ID: "d", Name: "/dns", Status: "Exited (0) 1 day ago",
+				ConfiguredPorts: []types.PortBinding{{HostPort: 5353, ContainerPort: 53, Protocol: "udp"}}},
+		}},
+	}
+
+	checkPortConflict(report, cfg)
+	got := map[string][]string{}
+	for _, is := range report.Issues {
+		got[is.Subject] = is.Facts["containers"].([]string)
+	}
+	want := map[string][]string{
+		"port=5432/tcp": {"db"},
+		"port=8080/tcp": {"web", "web-old"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for _, is := range report.Issues {
+		_, foreign := is.Facts["host_listener_addresses"]
+		if foreign != (is.Subject == "port=5432/tcp") {
+			t.Fatalf("%s: unexpected host listener facts %v", is.Subject, is.Facts)
+		}
+	}
+	for proto, want := range map[string]string{"tcp": "ss -lnpt ", "udp": "ss -lnpu ", "sctp": "ss -lnpS "} {
+		if got := ssCommand(proto, 5432); !strings.Contains(got, want) {
+			t.Fatalf("%s: expected %q in %q", proto, want, got)
+		}
+	}
+}
+
+func TestCheckDaemonConfig(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{DaemonConfig: config.DaemonConfigRule{Enabled: true}}}
+	icc := false
+	report := &types.Report{
+		Host: types.HostInfo{Routes: []types.Route{
+			{Iface: "wg0", Destination: "172.20.0.0/16"},
+			{Iface: "br-1a2b", Destination: "172.18.0.0/16"},
+		}},
+		Docker: types.DockerInfo{DaemonConfig: &types.DaemonConfig{
+			Path:         "/etc/docker/daemon.json",
+			Readable:     true,
+			Exists:       true,
+			Hosts:        []string{"unix:///var/run/docker.sock", "tcp://0.0.0.0:2375"},
+			LogOpts:      map[string]string{"max-size": "10m"},
+			LiveRestore:  true,
+			UsernsRemap:  "default",
+			ICC:          &icc,
+			AddressPools: []types.AddressPool{{Base: "172.16.0.0/12", Size: 24}},
+		}},
+	}
+
+	checkDaemonConfig(report, cfg)
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		got[is.RuleID] = is.Severity
+	}
+	want := map[string]string{
+		"DAEMON_TCP_WITHOUT_TLS":       "high",
+		"DAEMON_LOG_ROTATION_MISSING":  "low",
+		"DAEMON_DEFAULT_ULIMITS_UNSET": "low",
+		"DAEMON_ADDRESS_POOL_OVERLAP":  "high",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	report.Issues = nil
+	report.Docker.DaemonConfig = &types.DaemonConfig{Path: "/etc/docker/daemon.json", Readable: true, Exists: true, ParseError: "invalid JSON"}
+	checkDaemonConfig(report, cfg)
+	if len(report.Issues) != 1 || report.Issues[0].RuleID != "DAEMON_CONFIG_INVALID" {
+		t.Fatalf("expected only DAEMON_CONFIG_INVALID, got %+v", report.Issues)
+	}
+}
+
+func TestCheckDaemonWarnings(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{DaemonWarnings: config.DaemonWarningsRule{Enabled: true}}}
+	report := &types.Report{Docker: types.DockerInfo{Warnings: []string{
+		"API is accessible on http://0.0.0.0:2375 without encryption.",
+		"bridge-nf-call-iptables is disabled",
+		"bridge-nf-call-ip6tables is disabled",
+		"No swap limit support",
+		"Something new happened",
+	}}}
+
+	checkDaemonWarnings(report, cfg)
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		got[is.Subject] = is.Severity
+	}
+	want := map[string]string{
+		"daemon_warning=api_without_encryption": "high",
+		"daemon_warning=bridge_nf_call":         "medium",
+		"daemon_warning=no_swap_limit":          "low",
+		"daemon_warning=other":                  "low",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// daemon.json analysis already raised the unencrypted API; only that finding stays.
+	report = &types.Report{
+		Docker: types.DockerInfo{Warnings: []string{"API is accessible on http://0.0.0.0:2375 without encryption."}},
+		Issues: []types.Issue{{RuleID: "DAEMON_TCP_WITHOUT_TLS", Subject: "daemon_config", Severity: "high"}},
+	}
+	checkDaemonWarnings(report, cfg)
+	if len(report.Issues) != 1 {
+		t.Fatalf("expected no duplicate DAEMON_WARNINGS finding, got %+v", report.Issues)
+	}
+}
+
+func TestCheckStorageDriver(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{StorageDriver: config.StorageDriverRule{Enabled: true}}}
+	for _, tc := range []struct {
+		driver   string
+		status   map[string]string
+		severity string
+	}{
+		{"overlay2", map[string]string{"Backing Filesystem": "extfs", "Supports d_type": "true"}, ""},
+		{"overlay2", map[string]string{"Backing Filesystem": "xfs", "Supports d_type": "false"}, "high"},
+		{"aufs", nil, "medium"},
+		{"devicemapper", map[string]string{"Data file": "/dev/loop0"}, "high"},
+	} {
+		report := &types.Report{Docker: types.DockerInfo{
+			DaemonInfo:   map[string]interface{}{"storage_driver": tc.driver},
+			DriverStatus: tc.status,
+		}}
+		checkStorageDriver(report, cfg)
+		severity := ""
+		if len(report.Issues) > 0 {
+			severity = report.Issues[0].Severity
+		}
+		if len(report.Issues) > 1 || severity != tc.severity {
+			t.Fatalf("%s %v: expected severity %q, got %+v", tc.driver, tc.status, tc.severity, report.Issues)
+		}
+	}
+}
+
+func TestCheckEngineVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lifecycle.json")
+	data := `{"updated":"2025-01-01","series":[{"series":"24.0","eol":true,"eol_date":"2024-06"},{"series":"27"}],
+"known_issues":[{"id":"BUG-1","summary":"log rotation stalls","severity":"high","url":"https://example.com/bug-1","affected_series":["27"],"fixed_in":["27.2.0"],"affects_rules":["LOG_BLOAT"]}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Rules: config.Rules{EngineVersion: config.EngineVersionRule{Enabled: true, LifecycleFile: path, MaxTableAgeDays: 365}}}
+
+	for _, tc := range []struct {
+		version  string
+		severity string
+	}{
+		{"27.3.1", ""},
+		{"24.0.9", "medium"},
+		{"19.03.15", "medium"},
+		{"27.1.0", "high"},
+	} {
+		report := &types.Report{Docker: types.DockerInfo{Version: tc.version}}
+		checkEngineVersion(report, cfg)
+		severity := ""
+		if len(report.Issues) > 0 {
+			severity = report.Issues[0].Severity
+		}
+		if len(report.Issues) > 1 || severity != tc.severity {
+			t.Fatalf("%s: expected severity %q, got %+v", tc.version, tc.severity, report.Issues)
+		}
+	}
+
+	report := &types.Report{
+		Timestamp: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		Docker:    types.DockerInfo{Version: "27.1.0"},
+		Issues:    []types.Issue{{RuleID: "LOG_BLOAT", Subject: "container=web"}},
+	}
+	checkEngineVersion(report, cfg)
+	if len(report.Issues) != 2 {
+		t.Fatalf("expected 2 issues, got %+v", report.Issues)
+	}
+	if got := report.Issues[0].Facts["engine_known_issues"]; !reflect.DeepEqual(got, []string{"BUG-1"}) {
+		t.Fatalf("expected LOG_BLOAT to reference BUG-1, got %v", got)
+	}
+	engine := report.Issues[1]
+	if _, ok := engine.Facts["lifecycle_table_age_days"]; !ok {
+		t.Fatalf("expected stale table fact, got %v", engine.Facts)
+	}
+	if len(engine.References) != 1 || engine.References[0].Kind != "advisory" {
+		t.Fatalf("expected an advisory reference, got %+v", engine.References)
+	}
+
+	// A stale table is reported on its own even when nothing is known against the version.
+	report = &types.Report{
+		Timestamp: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		Docker:    types.DockerInfo{Version: "27.3.1"},
+	}
+	checkEngineVersion(report, cfg)
+	if len(report.Issues) != 1 || report.Issues[0].Severity != "low" || report.Issues[0].Facts["lifecycle_table_age_days"] != 516 {
+		t.Fatalf("expected a low stale-table issue, got %+v", report.Issues)
+	}
+}
+
+func TestCheckSysctl(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{Sysctl: config.SysctlRule{
+		Enabled: true,
+		Minimums: map[string]int64{
+			"net.ipv4.ip_forward":                1,
+			"net.bridge.bridge-nf-call-iptables": 1,
+			"vm.max_map_count":                   262144,
+			"kernel.pid_max":                     65536,
+		},
+		OvercommitMemory: []int{0, 1},
+	}}}
+	report := &types.Report{Host: types.HostInfo{Sysctls: map[string]string{
+		"net.ipv4.ip_forward":  "0",
+		"vm.max_map_count":     "65530",
+		"kernel.pid_max":       "4194304",
+		"vm.overcommit_memory": "2",
+	}}}
+	checkSysctl(report, cfg)
+
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		if is.RuleID != "SYSCTL_NOT_RECOMMENDED" {
+			t.Fatalf("unexpected issue %+v", is)
+		}
+		got[is.Subject] = is.Severity
+	}
+	want := map[string]string{
+		"sysctl=net.ipv4.ip_forward":                "high",
+		"sysctl=net.bridge.bridge-nf-call-iptables": "medium",
+		"sysctl=vm.max_map_count":                   "low",
+		"sysctl=vm.overcommit_memory":               "low",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// Settings dockerd already warned about are left to DAEMON_WARNINGS.
+	report = &types.Report{
+		Host: types.HostInfo{Sysctls: map[string]string{"net.ipv4.ip_forward": "0", "vm.max_map_count": "262144", "vm.overcommit_memory": "1"}},
+		Docker: types.DockerInfo{Warnings: []string{
+			"WARNING: IPv4 forwarding is disabled",
+			"WARNING: bridge-nf-call-iptables is disabled",
+		}},
+	}
+	dwCfg := *cfg
+	dwCfg.Rules.DaemonWarnings = config.DaemonWarningsRule{Enabled: true}
+	checkDaemonWarnings(report, &dwCfg)
+	checkSysctl(report, &dwCfg)
+	for _, is := range report.Issues {
+		if is.RuleID != "DAEMON_WARNINGS" {
+			t.Fatalf("expected only DAEMON_WARNINGS, got %+v", is)
+		}
+	}
+	if len(report.Issues) != 2 {
+		t.Fatalf("expected two daemon warnings, got %+v", report.Issues)
+	}
+
+	// Without /proc/sys nothing is reported.
+	report = &types.Report{}
+	checkSysctl(report, cfg)
+	if len(report.Issues) != 0 {
+		t.Fatalf("expected no issues, got %+v", report.Issues)
+	}
+}
+
+func TestCheckKernelVersion(t *testing.T) {
+	for _, tc := range []struct {
+		kernel    string
+		driver    string
+		cgroup    string
+		minKernel string
+		severity  string
+	}{
+		{"5.15.0-91-generic", "overlay2", "2", "", ""},
+		{"3.16.0-4-amd64", "overlay2", "", "", "high"},
+		{"3.10.0-1160.el7.x86_64", "overlay2", "1", "", ""},
+		{"3.10.0-327.el7.x86_64", "overlay2", "1", "", "high"},
+		{"4.19.0-25-amd64", "overlay2", "2", "", "low"},
+		{"4.9.0-8-amd64", "overlay2", "2", "", "medium"},
+		{"5.4.0-74-generic", "overlay2", "1", "5.10", "medium"},
+		{"6.6.31_1", "overlay2", "2", "5.10", ""},
+	} {
+		cfg := &config.Config{Rules: config.Rules{Sysctl: config.SysctlRule{Enabled: true, MinKernel: tc.minKernel}}}
+		report := &types.Report{
+			Host: types.HostInfo{Kernel: tc.kernel},
+			Docker: types.DockerInfo{
+				CgroupVersion: tc.cgroup,
+				DaemonInfo:    map[string]interface{}{"storage_driver": tc.driver},
+			},
+		}
+		checkSysctl(report, cfg)
+		severity := ""
+		if len(report.Issues) > 0 {
+			severity = report.Issues[0].Severity
+		}
+		if len(report.Issues) > 1 || severity != tc.severity {
+			t.Fatalf("%s: expected severity %q, got %+v", tc.kernel, tc.severity, report.Issues)
+		}
+	}
+}
+
+func TestCheckCgroup(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{Cgroup: config.CgroupRule{Enabled: true}}}
+	full := &types.LimitSupport{Memory: true, Swap: true, Pids: true, CPUQuota: true, CPUShares: true, CPUSet: true}
+
+	ruleIDs := func(report *types.Report) []string {
+		var ids []string
+		for _, is := range report.Issues {
+			ids = append(ids, is.RuleID)
+		}
+		return ids
+	}
+
+	// Healthy cgroup v2 host with the systemd driver.
+	report := &types.Report{
+		Host:   types.HostInfo{Cgroup: &types.HostCgroup{Version: "2", Systemd: true}},
+		Docker: types.DockerInfo{CgroupVersion: "2", CgroupDriver: "systemd", LimitSupport: full},
+	}
+	checkCgroup(report, cfg)
+	if len(report.Issues) != 0 {
+		t.Fatalf("expected no issues, got %+v", report.Issues)
+	}
+
+	// cgroup v1 with cgroupfs on systemd, no memory controller, and a container relying on --memory.
+	report = &types.Report{
+		Host: types.HostInfo{Cgroup: &types.HostCgroup{Version: "1", Systemd: true}},
+		Docker: types.DockerInfo{
+			CgroupVersion: "1",
+			CgroupDriver:  "cgroupfs",
+			LimitSupport:  &types.LimitSupport{Swap: false, Pids: true, CPUQuota: true},
+		},
+		Containers: types.Containers{List: []types.ContainerInfo{
+			{Name: "db", Limits: &types.ResourceLimits{Memory: 512 << 20}},
+		}},
+	}
+	checkCgroup(report, cfg)
+	want := []string{"CGROUP_V1", "CGROUP_DRIVER_MISMATCH", "CGROUP_CONTROLLERS_MISSING"}
+	if got := ruleIDs(report); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	missing := report.Issues[2]
+	if missing.Severity != "high" || !reflect.DeepEqual(missing.Facts["missing_controllers"], []string{"memory", "swap"}) {
+		t.Fatalf("unexpected controllers issue %+v", missing)
+	}
+
+	// Missing swap accounting is left to DAEMON_WARNINGS when dockerd already warned.
+	report = &types.Report{
+		Host: types.HostInfo{Cgroup: &types.HostCgroup{Version: "2", Systemd: true}},
+		Docker: types.DockerInfo{
+			CgroupVersion: "2",
+			CgroupDriver:  "systemd",
+			LimitSupport:  &types.LimitSupport{Memory: true, Pids: true, CPUQuota: true, CPUShares: true, CPUSet: true},
+			Warnings:      []string{"WARNING: No swap limit support"},
+		},
+	}
+	checkDaemonWarnings(report, &config.Config{Rules: config.Rules{DaemonWarnings: config.DaemonWarningsRule{Enabled: true}}})
+	checkCgroup(report, cfg)
+	if got := ruleIDs(report); !reflect.DeepEqual(got, []string{"DAEMON_WARNINGS"}) {
+		t.Fatalf("expected only the daemon warning, got %+v", report.Issues)
+	}
+}
+
+func TestContainerLogPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		lc      types.LogConfig
+		bounded bool
+		summary string
+	}{
+		{types.LogConfig{Driver: "json-file"}, false, "unbounded"},
+		{types.LogConfig{Driver: "json-file", Opts: map[string]string{"max-size": "-1"}}, false, "unbounded"},
+		{types.LogConfig{Driver: "json-file", Opts: map[string]string{"max-size": "10m", "max-file": "3"}}, true, "10m x 3 files"},
+		{types.LogConfig{Driver: "local"}, true, "20m x 5 files (defaults)"},
+		{types.LogConfig{Driver: "none"}, true, "no logs kept"},
+		{types.LogConfig{Driver: "journald"}, false, "shipped by the journald driver; size not measured"},
+	} {
+		p := ContainerLogPolicy(&tc.lc)
+		if p.Bounded != tc.bounded || p.Summary != tc.summary {
+			t.Fatalf("%+v: expected bounded=%v %q, got %+v", tc.lc, tc.bounded, tc.summary, p)
+		}
+	}
+}
+
+func TestCheckLogRotation(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{LogRotation: config.LogRotationRule{Enabled: true, IgnoreDrivers: []string{"syslog"}}}}
+	report := &types.Report{Containers: types.Containers{List: []types.ContainerInfo{
+		{ID: "a1", Name: "/api", LogConfig: &types.LogConfig{Driver: "json-file"}, LogSize: 1 << 30},
+		{ID: "b2", Name: "/web", LogConfig: &types.LogConfig{Driver: "json-file", Opts: map[string]string{"max-size": "10m"}}},
+		{ID: "c3", Name: "/db", LogConfig: &types.LogConfig{Driver: "json-file", Opts: map[string]string{"max-size": "10m", "max-file": "3"}}},
+		{ID: "d4", Name: "/cache", LogConfig: &types.LogConfig{Driver: "local"}},
+		{ID: "e5", Name: "/worker", LogConfig: &types.LogConfig{Driver: "journald"}},
+		{ID: "f6", Name: "/batch", LogConfig: &types.LogConfig{Driver: "journald"}},
+		{ID: "g7", Name: "/legacy", LogConfig: &types.LogConfig{Driver: "syslog"}},
+		{ID: "h8", Name: "/unknown"},
+	}}}
+	checkLogRotation(report, cfg)
+
+	got := map[string]string{}
+	for _, is := range report.Issues {
+		got[is.Subject] = is.Severity
+	}
+	want := map[string]string{
+		"container=a1":        "medium",
+		"container=b2":        "low",
+		"log_driver=journald": "low",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if names := report.Issues[2].Facts["containers"]; !reflect.DeepEqual(names, []string{"worker", "batch"}) {
+		t.Fatalf("expected journald containers, got %v", names)
+	}
+}
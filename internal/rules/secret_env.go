@@ -0,0 +1,61 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func checkSecretEnv(report *types.Report, cfg *config.Config) {
+	// SECRET_IN_ENV
+	rule := cfg.Rules.SecretEnv
+	if !rule.Enabled {
+		return
+	}
+	for _, container := range report.Containers.List {
+		var vars []map[string]interface{}
+		var names []string
+		severity := "medium"
+		for _, v := range container.SensitiveEnv {
+			if containsString(rule.IgnoreNames, v.Name) {
+				continue
+			}
+			// The collector never keeps values; evidence is limited to name, kind and keyed fingerprint.
+			vars = append(vars, map[string]interface{}{
+				"name":       v.Name,
+				"kind":       v.Kind,
+				"matched_by": v.MatchedBy,
+				"value_hmac": v.ValueHMAC,
+			})
+			names = append(names, v.Name)
+			if v.MatchedBy == "value" {
+				severity = "high"
+			}
+		}
+		if len(vars) == 0 {
+			continue
+		}
+
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "SECRET_IN_ENV",
+			Subject:     "container=" + container.ID,
+			Severity:    severity,
+			Category:    "security",
+			Description: fmt.Sprintf("Container %s (%s) has %d secret-looking environment variable(s): %s", container.Name, container.ID, len(vars), strings.Join(names, ", ")),
+			Facts: map[string]interface{}{
+				"container_id":   container.ID,
+				"container_name": container.Name,
+				"variables":      vars,
+			},
+			Solutions: []string{
+				"Environment variables are visible to anyone who can run 'docker inspect' and are often copied into logs, crash reports and child processes.",
+				"Use Docker secrets ('docker secret create' with Swarm, or 'secrets:' in compose) mounted under /run/secrets instead.",
+				"Many images read '<NAME>_FILE' variants (e.g. POSTGRES_PASSWORD_FILE); point them at a mounted secret file.",
+				"Rotate any credential that was passed via '-e' or an env file, since it is stored in the container config.",
+				"If a variable is not a secret, add its name to rules.secret_env.ignore_names. Matching value_hmac across containers means the same value is reused.",
+			},
+		})
+	}
+}
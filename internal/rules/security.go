@@ -0,0 +1,195 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// cisBenchmarkURL is the landing page of the CIS Docker Benchmark; control numbers
+// below follow version 1.2.0 (as used by docker-bench-security).
+const cisBenchmarkURL = "https://www.cisecurity.org/benchmark/docker"
+
+func cisReference(control, title string) []types.Reference {
+	return []types.Reference{{
+		Kind:  "cis",
+		Label: fmt.Sprintf("CIS Docker Benchmark v1.2.0 %s: %s", control, title),
+		URL:   cisBenchmarkURL,
+	}}
+}
+
+// dangerousCapabilities are added capabilities that break container isolation.
+var dangerousCapabilities = map[string]string{
+	"ALL":        "high",
+	"SYS_ADMIN":  "high",
+	"SYS_PTRACE": "medium",
+	"NET_ADMIN":  "medium",
+}
+
+func securityIgnored(rule config.SecurityRule, c types.ContainerInfo) bool {
+	name := strings.TrimPrefix(c.Name, "/")
+	for _, ig := range rule.IgnoreContainers {
+		ig = strings.TrimPrefix(strings.TrimSpace(ig), "/")
+		if ig == name || (ig != "" && strings.HasPrefix(c.ID, ig)) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSecurityOpt(opts []string, key, value string) bool {
+	for _, o := range opts {
+		// Both "seccomp=unconfined" and the legacy "seccomp:unconfined" are accepted by the daemon.
+		if o == key+"="+value || o == key+":"+value {
+			return true
+		}
+	}
+	return false
+}
+
+func runsAsRoot(user string) bool {
+	u, _, _ := strings.Cut(strings.TrimSpace(user), ":")
+	return u == "" || u == "0" || u == "root"
+}
+
+func checkSecurity(report *types.Report, cfg *config.Config) {
+	// CONTAINER_PRIVILEGED / CONTAINER_DANGEROUS_CAPABILITIES / CONTAINER_DOCKER_SOCKET_MOUNTED /
+	// CONTAINER_HOST_NETWORK / CONTAINER_HOST_PID / CONTAINER_HOST_IPC / CONTAINER_SECCOMP_DISABLED /
+	// CONTAINER_APPARMOR_DISABLED / CONTAINER_ROOTFS_WRITABLE / CONTAINER_RUNS_AS_ROOT
+	rule := cfg.Rules.Security
+	if !rule.Enabled {
+		return
+	}
+	for _, container := range report.Containers.List {
+		sec := container.Security
+		if sec == nil || securityIgnored(rule, container) {
+			continue
+		}
+		add := func(ruleID, severity, description string, extra map[string]interface{}, solutions []string, refs []types.Reference) {
+			factsMap := map[string]interface{}{
+				"container_id":   container.ID,
+				"container_name": container.Name,
+			}
+			for k, v := range extra {
+				factsMap[k] = v
+			}
+			solutions = append(solutions, fmt.Sprintf("If this is intended, add %q to rules.security.ignore_containers.", strings.TrimPrefix(container.Name, "/")))
+			report.Issues = append(report.Issues, types.Issue{
+				RuleID:      ruleID,
+				Subject:     "container=" + container.ID,
+				Severity:    severity,
+				Category:    "security",
+				Description: fmt.Sprintf("Container %s (%s) %s", container.Name, container.ID, description),
+				Facts:       factsMap,
+				Solutions:   solutions,
+				References:  refs,
+			})
+		}
+
+		if sec.Privileged {
+			add("CONTAINER_PRIVILEGED", "high", "runs in privileged mode with all capabilities and host devices", nil,
+				[]string{
+					"Remove '--privileged' ('privileged: true' in compose); a privileged container can take over the host.",
+					"Grant only the capabilities and devices the workload needs ('--cap-add', '--device').",
+				},
+				cisReference("5.4", "Ensure that privileged containers are not used"))
+		}
+
+		var dangerous []string
+		severity := "medium"
+		for _, capName := range sec.CapAdd {
+			c := strings.TrimPrefix(strings.ToUpper(capName), "CAP_")
+			if sev, ok := dangerousCapabilities[c]; ok {
+				dangerous = append(dangerous, c)
+				if sev == "high" {
+					severity = "high"
+				}
+			}
+		}
+		if len(dangerous) > 0 {
+			sort.Strings(dangerous)
+			add("CONTAINER_DANGEROUS_CAPABILITIES", severity, fmt.Sprintf("adds dangerous capabilities: %s", strings.Join(dangerous, ", ")),
+				map[string]interface{}{"dangerous_capabilities": dangerous, "cap_add": sec.CapAdd},
+				[]string{
+					"Drop capabilities the workload does not need; start from '--cap-drop ALL' and add back individual capabilities.",
+					"SYS_ADMIN is close to root on the host; SYS_PTRACE allows reading other processes' memory; NET_ADMIN allows reconfiguring networking.",
+				},
+				cisReference("5.3", "Ensure that Linux kernel capabilities are restricted within containers"))
+		}
+
+		for _, m := range container.Mounts {
+			if m.Type != "bind" || (m.Source != "/var/run/docker.sock" && m.Source != "/run/docker.sock") {
+				continue
+			}
+			add("CONTAINER_DOCKER_SOCKET_MOUNTED", "high", fmt.Sprintf("has the Docker socket %s mounted at %s", m.Source, m.Destination),
+				map[string]interface{}{"source": m.Source, "destination": m.Destination, "rw": m.RW},
+				[]string{
+					"Access to the Docker socket is root on the host, even when mounted read-only.",
+					"Use a socket proxy that only allows the API calls the container needs, or remove the mount.",
+				},
+				cisReference("5.31", "Ensure that the Docker socket is not mounted inside any containers"))
+			break
+		}
+
+		namespaces := []struct {
+			ruleID, mode, name, control, title string
+		}{
+			{"CONTAINER_HOST_NETWORK", sec.NetworkMode, "network", "5.9", "Ensure that the host's network namespace is not shared"},
+			{"CONTAINER_HOST_PID", sec.PidMode, "PID", "5.15", "Ensure that the host's process namespace is not shared"},
+			{"CONTAINER_HOST_IPC", sec.IpcMode, "IPC", "5.16", "Ensure that the host's IPC namespace is not shared"},
+		}
+		for _, ns := range namespaces {
+			if ns.mode != "host" {
+				continue
+			}
+			add(ns.ruleID, "medium", fmt.Sprintf("shares the host %s namespace", ns.name),
+				map[string]interface{}{"namespace": strings.ToLower(ns.name), "mode": ns.mode},
+				[]string{
+					fmt.Sprintf("Remove '--%s host' unless the workload needs to see host %s resources.", strings.ToLower(ns.name), ns.name),
+					"Processes in the container can then observe or interfere with host services.",
+				},
+				cisReference(ns.control, ns.title))
+		}
+
+		if hasSecurityOpt(sec.SecurityOpt, "seccomp", "unconfined") {
+			add("CONTAINER_SECCOMP_DISABLED", "medium", "runs with the seccomp profile disabled",
+				map[string]interface{}{"security_opt": sec.SecurityOpt},
+				[]string{
+					"Remove '--security-opt seccomp=unconfined'; the default profile blocks syscalls rarely needed by applications.",
+					"If a specific syscall is required, ship a custom profile: '--security-opt seccomp=/path/profile.json'.",
+				},
+				cisReference("5.21", "Ensure that the default seccomp profile is not Disabled"))
+		}
+
+		if hasSecurityOpt(sec.SecurityOpt, "apparmor", "unconfined") || sec.AppArmorProfile == "unconfined" {
+			add("CONTAINER_APPARMOR_DISABLED", "medium", "runs without an AppArmor profile",
+				map[string]interface{}{"apparmor_profile": sec.AppArmorProfile, "security_opt": sec.SecurityOpt},
+				[]string{
+					"Remove '--security-opt apparmor=unconfined' so the default 'docker-default' profile applies.",
+				},
+				cisReference("5.1", "Ensure that, if applicable, an AppArmor Profile is enabled"))
+		}
+
+		if !sec.ReadonlyRootfs {
+			add("CONTAINER_ROOTFS_WRITABLE", "low", "has a writable root filesystem", nil,
+				[]string{
+					"Run with '--read-only' ('read_only: true' in compose) and mount volumes or '--tmpfs' for paths that must be writable.",
+					"A read-only root filesystem stops attackers from persisting tools inside the container.",
+				},
+				cisReference("5.12", "Ensure that the container's root filesystem is mounted as read only"))
+		}
+
+		if runsAsRoot(sec.User) {
+			add("CONTAINER_RUNS_AS_ROOT", "low", "runs as root (UID 0)",
+				map[string]interface{}{"user": sec.User},
+				[]string{
+					"Add a 'USER' instruction to the image or run with '--user <uid>:<gid>'.",
+					"Enable user namespace remapping ('userns-remap' in daemon.json) to map container root to an unprivileged host user.",
+				},
+				cisReference("4.1", "Ensure that a user for the container has been created"))
+		}
+	}
+}
@@ -63,6 +63,11 @@ func checkStorageBloat(report *types.Report, cfg *config.Config, df *facts.Docke
 			solutions = append(solutions, fmt.Sprintf("Build cache size: %s - consider pruning if large.", humanBytes(buildCacheSize)))
 		}
 
+		var actions []types.Action
+		if buildCacheSize > 0 {
+			// Build cache is always reproducible; only prune entries idle for a week.
+			actions = append(actions, types.Action{Type: "builder_prune", Risk: "safe", Params: map[string]string{"until": "168h"}})
+		}
 		report.Issues = append(report.Issues, types.Issue{
 			RuleID:      "DOCKER_STORAGE_BLOAT",
 			Subject:     "images_total",
@@ -71,6 +76,7 @@ func checkStorageBloat(report *types.Report, cfg *config.Config, df *facts.Docke
 			Description: fmt.Sprintf("Docker image disk usage is %d bytes, exceeding threshold of %d bytes", imageSizeObserved, cfg.Rules.StorageBloat.ImageSizeThreshold),
 			Facts:       factsMap,
 			Solutions:   solutions,
+			Actions:     actions,
 		})
 	}
 }
\ No newline at end of file
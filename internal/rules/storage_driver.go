@@ -0,0 +1,75 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// deprecatedStorageDrivers maps legacy storage drivers to the reason they should be replaced.
+var deprecatedStorageDrivers = map[string]string{
+	"aufs":         "aufs is deprecated and no longer shipped with current Docker Engine releases",
+	"devicemapper": "devicemapper is deprecated and slated for removal; loopback mode in particular is slow and unsafe for production",
+	"overlay":      "the legacy overlay driver is deprecated in favour of overlay2 and uses far more inodes",
+}
+
+func checkStorageDriver(report *types.Report, cfg *config.Config) {
+	// STORAGE_DRIVER_UNSUPPORTED
+	if !cfg.Rules.StorageDriver.Enabled {
+		return
+	}
+	driver, _ := report.Docker.DaemonInfo["storage_driver"].(string)
+	status := report.Docker.DriverStatus
+
+	var problems []string
+	severity := "medium"
+	if reason, ok := deprecatedStorageDrivers[driver]; ok {
+		problems = append(problems, reason)
+	}
+	if strings.EqualFold(status["Supports d_type"], "false") {
+		problems = append(problems, "the backing filesystem does not support d_type, so overlay whiteouts and directory renames can silently break image layers")
+		severity = "high"
+	}
+	if driver == "devicemapper" && strings.Contains(strings.ToLower(status["Data file"]), "/dev/loop") {
+		severity = "high"
+	}
+	if len(problems) == 0 {
+		return
+	}
+
+	facts := map[string]interface{}{"storage_driver": driver}
+	for _, k := range []string{"Backing Filesystem", "Supports d_type", "Data file"} {
+		if v, ok := status[k]; ok {
+			facts[strings.ToLower(strings.ReplaceAll(k, " ", "_"))] = v
+		}
+	}
+
+	solutions := []string{
+		"Migrate to overlay2: set \"storage-driver\": \"overlay2\" in daemon.json; existing images and containers are not migrated, so export or push what you need first.",
+	}
+	if strings.EqualFold(status["Supports d_type"], "false") {
+		solutions = append(solutions,
+			fmt.Sprintf("Recreate the backing filesystem (%s) with d_type support: XFS needs 'mkfs.xfs -n ftype=1'; ext4 supports it by default.", fallbackString(status["Backing Filesystem"], "unknown")),
+			"Check with 'xfs_info <mount point> | grep ftype' (must be ftype=1).",
+		)
+	}
+
+	report.Issues = append(report.Issues, types.Issue{
+		RuleID:      "STORAGE_DRIVER_UNSUPPORTED",
+		Subject:     "storage_driver=" + driver,
+		Severity:    severity,
+		Category:    "configuration",
+		Description: fmt.Sprintf("Storage driver %q is not a supported setup: %s", driver, strings.Join(problems, "; ")),
+		Facts:       facts,
+		Solutions:   solutions,
+	})
+}
+
+func fallbackString(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
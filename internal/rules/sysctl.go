@@ -0,0 +1,230 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/lifecycle"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// sysctlAdvice describes why a kernel parameter matters for containers.
+type sysctlAdvice struct {
+	severity  string
+	impact    string
+	solutions []string
+	// daemonWarning is the DAEMON_WARNINGS class dockerd raises for the same setting;
+	// when it already fired, the sysctl finding would only repeat it.
+	daemonWarning string
+}
+
+var knownSysctls = map[string]sysctlAdvice{
+	"net.ipv4.ip_forward": {
+		severity:      "high",
+		impact:        "Containers on bridge networks cannot reach anything outside the host and published ports stop working.",
+		solutions:     []string{"Check that no firewall script or cloud-init module turns forwarding off after dockerd starts."},
+		daemonWarning: "ip_forward_disabled",
+	},
+	"net.bridge.bridge-nf-call-iptables": {
+		severity:      "medium",
+		impact:        "Traffic between containers on the same bridge bypasses iptables, so published-port rules and network isolation may not apply.",
+		solutions:     []string{"Load the br_netfilter module first: 'sudo modprobe br_netfilter' and add it to /etc/modules-load.d/."},
+		daemonWarning: "bridge_nf_call",
+	},
+	"fs.inotify.max_user_watches": {
+		severity: "low",
+		impact:   "File watchers in containers (dev servers, log shippers, config reloaders) fail with ENOSPC once the host-wide limit is used up.",
+	},
+	"fs.inotify.max_user_instances": {
+		severity: "low",
+		impact:   "Processes in containers fail to create inotify instances ('too many open files'); the limit is shared by every container of the same UID.",
+	},
+	"vm.max_map_count": {
+		severity: "low",
+		impact:   "Elasticsearch, OpenSearch and other mmap-heavy workloads refuse to start or crash.",
+	},
+	"fs.file-max": {
+		severity: "low",
+		impact:   "All containers share the system-wide file handle limit; busy hosts fail with 'Too many open files in system'.",
+	},
+	"kernel.pid_max": {
+		severity: "low",
+		impact:   "All containers share the PID space of the host; many containers or a fork-heavy one can exhaust it and make fork fail everywhere.",
+	},
+}
+
+var overcommitModes = map[int]string{
+	0: "heuristic overcommit",
+	1: "always overcommit",
+	2: "strict accounting (no overcommit)",
+}
+
+func checkSysctl(report *types.Report, cfg *config.Config) {
+	// SYSCTL_NOT_RECOMMENDED / KERNEL_TOO_OLD
+	rule := cfg.Rules.Sysctl
+	if !rule.Enabled {
+		return
+	}
+	checkKernelVersion(report, rule)
+
+	sysctls := report.Host.Sysctls
+	if sysctls == nil {
+		return
+	}
+	names := make([]string, 0, len(rule.Minimums))
+	for name := range rule.Minimums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	add := func(name, severity, description string, facts map[string]interface{}, solutions []string) {
+		facts["sysctl"] = name
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "SYSCTL_NOT_RECOMMENDED",
+			Subject:     "sysctl=" + name,
+			Severity:    severity,
+			Category:    "host",
+			Description: description,
+			Facts:       facts,
+			Solutions:   solutions,
+		})
+	}
+
+	for _, name := range names {
+		min := rule.Minimums[name]
+		if min <= 0 {
+			continue
+		}
+		advice, known := knownSysctls[name]
+		if !known {
+			advice = sysctlAdvice{severity: "low", impact: "The value is below the configured recommendation."}
+		}
+		if advice.daemonWarning != "" && daemonWarningReported(report, advice.daemonWarning) {
+			continue
+		}
+		persist := fmt.Sprintf("Persist it in /etc/sysctl.d/99-docker.conf ('%s = %d') and apply with 'sudo sysctl --system'.", name, min)
+		raw, ok := sysctls[name]
+		if !ok {
+			// Only bridge-nf-call-* disappears on a running Linux host: br_netfilter is not loaded.
+			if strings.HasPrefix(name, "net.bridge.") {
+				add(name, advice.severity, fmt.Sprintf("%s is not available (br_netfilter module not loaded). %s", name, advice.impact),
+					map[string]interface{}{"recommended_min": min},
+					append(append([]string{}, advice.solutions...), fmt.Sprintf("Then enable it: 'sudo sysctl -w %s=%d'.", name, min), persist))
+			}
+			continue
+		}
+		fields := strings.Fields(raw)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil || value >= min {
+			continue
+		}
+		add(name, advice.severity, fmt.Sprintf("%s is %d, below the recommended %d. %s", name, value, min, advice.impact),
+			map[string]interface{}{"value": value, "recommended_min": min},
+			append([]string{fmt.Sprintf("Raise it: 'sudo sysctl -w %s=%d'.", name, min), persist}, advice.solutions...))
+	}
+
+	if len(rule.OvercommitMemory) > 0 {
+		if raw, ok := sysctls["vm.overcommit_memory"]; ok {
+			if value, err := strconv.Atoi(raw); err == nil && !containsInt(rule.OvercommitMemory, value) {
+				desc := fmt.Sprintf("vm.overcommit_memory is %d (%s), not one of the accepted values %v.", value, overcommitModes[value], rule.OvercommitMemory)
+				if value == 2 {
+					desc += " Strict accounting makes fork and large allocations fail in containers long before memory is actually used."
+				}
+				want := rule.OvercommitMemory[0]
+				add("vm.overcommit_memory", "low", desc,
+					map[string]interface{}{"value": value, "accepted": rule.OvercommitMemory},
+					[]string{
+						fmt.Sprintf("Set it: 'sudo sysctl -w vm.overcommit_memory=%d' and persist it in /etc/sysctl.d/99-docker.conf.", want),
+						"Some workloads document their own requirement (e.g. Redis recommends 1); configure rules.sysctl.overcommit_memory accordingly.",
+					})
+			}
+		}
+	}
+}
+
+// kernelRelease splits a kernel release such as "3.10.0-1160.el7.x86_64" into its
+// version ("3.10.0") and the numeric distribution patch level (1160, or 0).
+func kernelRelease(kernel string) (string, int) {
+	version, rest, _ := strings.Cut(kernel, "-")
+	end := 0
+	for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+		end++
+	}
+	patch, _ := strconv.Atoi(rest[:end])
+	return version, patch
+}
+
+func checkKernelVersion(report *types.Report, rule config.SysctlRule) {
+	kernel := report.Host.Kernel
+	version, patch := kernelRelease(kernel)
+	if !lifecycle.Valid(version) {
+		// Unparseable releases (e.g. "6.6.31_1") would compare as 0.0 and look ancient.
+		return
+	}
+	driver, _ := report.Docker.DaemonInfo["storage_driver"].(string)
+
+	var reasons []string
+	severity := ""
+	raise := func(s string) {
+		if severityRank[s] > severityRank[severity] {
+			severity = s
+		}
+	}
+	if driver == "overlay2" && lifecycle.Compare(version, "4.0") < 0 {
+		// RHEL/CentOS 7 backported overlay2 support to 3.10.0-514.
+		if !(strings.Contains(kernel, ".el7") && lifecycle.Compare(version, "3.10.0") == 0 && patch >= 514) {
+			reasons = append(reasons, "the overlay2 storage driver needs kernel 4.0 or later (3.10.0-514 or later on RHEL/CentOS 7)")
+			raise("high")
+		}
+	}
+	if report.Docker.CgroupVersion == "2" {
+		switch {
+		case lifecycle.Compare(version, "4.15") < 0:
+			reasons = append(reasons, "cgroup v2 needs kernel 4.15 or later")
+			raise("medium")
+		case lifecycle.Compare(version, "5.2") < 0:
+			reasons = append(reasons, "cgroup v2 is recommended with kernel 5.2 or later (the cgroup v2 freezer arrived in 5.2)")
+			raise("low")
+		}
+	}
+	if rule.MinKernel != "" && lifecycle.Compare(version, rule.MinKernel) < 0 {
+		reasons = append(reasons, "the configured minimum is "+rule.MinKernel)
+		raise("medium")
+	}
+	if len(reasons) == 0 {
+		return
+	}
+
+	report.Issues = append(report.Issues, types.Issue{
+		RuleID:      "KERNEL_TOO_OLD",
+		Subject:     "kernel=" + kernel,
+		Severity:    severity,
+		Category:    "host",
+		Description: fmt.Sprintf("Kernel %s is too old for this Docker setup: %s", kernel, strings.Join(reasons, "; ")),
+		Facts: map[string]interface{}{
+			"kernel":         kernel,
+			"storage_driver": driver,
+			"cgroup_version": report.Docker.CgroupVersion,
+			"min_kernel":     rule.MinKernel,
+		},
+		Solutions: []string{
+			"Upgrade to a maintained distribution kernel (e.g. the distribution's HWE/LTS kernel) and reboot.",
+			"If the kernel cannot be upgraded, switch the affected feature off: use a storage driver the kernel supports or boot with cgroup v1.",
+		},
+	})
+}
+
+func containsInt(list []int, v int) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
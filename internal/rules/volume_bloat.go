@@ -59,6 +59,12 @@ func checkVolumeBloat(report *types.Report) {
 				"Review container configurations to ensure volumes are properly attached.",
 			}...)
 
+			// Removing a volume deletes its data, so these actions are always risky.
+			actions := make([]types.Action, 0, len(unusedVolumes))
+			for _, v := range unusedVolumes {
+				actions = append(actions, types.Action{Type: "volume_remove", Target: v.id, Risk: "risky"})
+			}
+
 			report.Issues = append(report.Issues, types.Issue{
 				RuleID:      "VOLUME_BLOAT",
 				Subject:     "volumes_unused",
@@ -67,6 +73,7 @@ func checkVolumeBloat(report *types.Report) {
 				Description: fmt.Sprintf("Found %d unused Docker volumes that can be cleaned up", len(unusedVolumes)),
 				Facts:       facts,
 				Solutions:   solutions,
+				Actions:     actions,
 			})
 		}
 	}
@@ -0,0 +1,61 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func checkWritableLayer(report *types.Report, cfg *config.Config) {
+	// CONTAINER_WRITABLE_LAYER_LARGE
+	if !cfg.Rules.WritableLayer.Enabled {
+		return
+	}
+	threshold := cfg.Rules.WritableLayer.SizeThreshold
+	for _, container := range report.Containers.List {
+		if container.SizeRw <= 0 || uint64(container.SizeRw) <= threshold {
+			continue
+		}
+		size := uint64(container.SizeRw)
+		severity := "medium"
+		if size > threshold*2 {
+			severity = "high"
+		}
+
+		factsMap := map[string]interface{}{
+			"container_id":   container.ID,
+			"container_name": container.Name,
+			"size_rw":        size,
+			"threshold":      threshold,
+		}
+		solutions := []string{
+			fmt.Sprintf("See what the container writes: 'docker diff %s'", container.ID),
+			"Mount a volume (or bind mount) at the paths the application writes to, instead of the container filesystem.",
+			"Use '--read-only' with '--tmpfs' for scratch paths to catch unexpected writes.",
+			"Recreating the container resets its writable layer; data written there is lost.",
+		}
+		if len(container.WritableTopPaths) > 0 {
+			top := make([]string, 0, len(container.WritableTopPaths))
+			for _, p := range container.WritableTopPaths {
+				if p.Bytes > 0 {
+					top = append(top, fmt.Sprintf("%s (%s, %d changes)", p.Path, humanBytes(p.Bytes), p.Changes))
+				} else {
+					top = append(top, fmt.Sprintf("%s (%d changes)", p.Path, p.Changes))
+				}
+			}
+			factsMap["top_paths"] = top
+			solutions = append(solutions, fmt.Sprintf("Top changed paths: %v", top))
+		}
+
+		report.Issues = append(report.Issues, types.Issue{
+			RuleID:      "CONTAINER_WRITABLE_LAYER_LARGE",
+			Subject:     "container=" + container.ID,
+			Severity:    severity,
+			Category:    "storage_bloat",
+			Description: fmt.Sprintf("Container %s (%s) has written %s into its writable layer, exceeding threshold of %s", container.Name, container.ID, humanBytes(size), humanBytes(threshold)),
+			Facts:       factsMap,
+			Solutions:   solutions,
+		})
+	}
+}
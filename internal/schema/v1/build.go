@@ -10,6 +10,9 @@ import (
 
 	"github.com/dashu-baba/docker-doctor/internal/collector"
 	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/facts"
+	"github.com/dashu-baba/docker-doctor/internal/plan"
+	"github.com/dashu-baba/docker-doctor/internal/rules"
 	"github.com/dashu-baba/docker-doctor/internal/types"
 )
 
@@ -81,6 +84,14 @@ func BuildFromV0(ctx context.Context, v0 *types.Report, cfg *config.Config, apiV
 			Errors:     []string{"host filesystem collector not enabled in this build"},
 		},
 	}
+	if cfg.Scan.Sample > 0 {
+		stats := Collector{Name: "container_stats", Status: "ok", DurationMs: cfg.Scan.Sample.Milliseconds(), Errors: []string{}}
+		if errs := v0.CollectorErrors["container_stats"]; len(errs) > 0 {
+			stats.Status = "error"
+			stats.Errors = errs
+		}
+		collectors = append(collectors, stats)
+	}
 	if dfErr != nil {
 		for i := range collectors {
 			if collectors[i].Name == "docker_system_df" {
@@ -120,15 +131,15 @@ func BuildFromV0(ctx context.Context, v0 *types.Report, cfg *config.Config, apiV
 			Capabilities: Capabilities{
 				DockerAPI:                 true,
 				HostFSMounted:             false,
-				DaemonConfigReadable:      false,
+				DaemonConfigReadable:      v0.Docker.DaemonConfig != nil && v0.Docker.DaemonConfig.Readable,
 				ContainerLogFilesReadable: false,
 			},
 			Redaction: Redaction{
 				Enabled:         false,
 				MaskedIPs:       false,
 				MaskedHostnames: false,
-				DroppedEnvVars:  false,
-				Notes:           []string{},
+				DroppedEnvVars:  true,
+				Notes:           []string{"Container environment values are never collected; secret-looking variables are reported by name and a truncated HMAC-SHA256 of the value (keyed per run, or by rules.secret_env.hash_key_env)."},
 			},
 		},
 		Target: Target{
@@ -145,6 +156,7 @@ func BuildFromV0(ctx context.Context, v0 *types.Report, cfg *config.Config, apiV
 				APIVersion:     apiVersion,
 				StorageDriver:  stringFromDaemonInfo(v0.Docker.DaemonInfo, "storage_driver"),
 				CgroupVersion:  v0.Docker.CgroupVersion,
+				CgroupDriver:   v0.Docker.CgroupDriver,
 				DataRoot:       v0.Docker.DataRoot,
 			},
 		},
@@ -159,6 +171,8 @@ func BuildFromV0(ctx context.Context, v0 *types.Report, cfg *config.Config, apiV
 			},
 			ResourceSnapshot: SummaryResourceSnapshot{
 				DockerSystemDf: systemDf,
+				Limits:         buildLimitsSummary(v0),
+				Filesystems:    buildFilesystems(v0),
 			},
 			FindingCounts: counts,
 		},
@@ -168,7 +182,114 @@ func BuildFromV0(ctx context.Context, v0 *types.Report, cfg *config.Config, apiV
 			Included: false,
 			Reason:   "privacy_and_size",
 		},
+		CleanupPlan: buildCleanupPlan(df, finishedAt),
+		Inventory:   buildInventory(v0),
+	}
+}
+
+// buildInventory lists published ports and log policies per container; nil when there is neither.
+func buildInventory(v0 *types.Report) *Inventory {
+	var ports []PublishedPort
+	var logs []LogPolicy
+	for _, c := range v0.Containers.List {
+		if c.LogConfig != nil {
+			p := rules.ContainerLogPolicy(c.LogConfig)
+			logs = append(logs, LogPolicy{
+				ContainerID:   c.ID,
+				ContainerName: strings.TrimPrefix(c.Name, "/"),
+				Driver:        p.Driver,
+				Policy:        p.Summary,
+				Bounded:       p.Bounded,
+				LogSizeBytes:  c.LogSize,
+			})
+		}
+		for _, p := range c.Ports {
+			service, ok := rules.SensitivePortService(p.ContainerPort)
+			if !ok {
+				service, _ = rules.SensitivePortService(p.HostPort)
+			}
+			ports = append(ports, PublishedPort{
+				ContainerID:   c.ID,
+				ContainerName: strings.TrimPrefix(c.Name, "/"),
+				HostIP:        p.HostIP,
+				HostPort:      p.HostPort,
+				ContainerPort: p.ContainerPort,
+				Protocol:      p.Protocol,
+				Public:        rules.IsPublicBinding(p),
+				Service:       service,
+			})
+		}
+	}
+	if len(ports) == 0 && len(logs) == 0 {
+		return nil
+	}
+	return &Inventory{PublishedPorts: ports, LogPolicies: logs}
+}
+
+// buildFilesystems lists host disk usage sorted by path.
+func buildFilesystems(v0 *types.Report) []Filesystem {
+	out := make([]Filesystem, 0, len(v0.Host.DiskUsage))
+	for path, d := range v0.Host.DiskUsage {
+		if d == nil {
+			continue
+		}
+		out = append(out, Filesystem{
+			Path:              path,
+			UsedBytes:         d.Used,
+			TotalBytes:        d.Total,
+			UsedPercent:       d.UsedPercent,
+			InodesUsed:        d.InodesUsed,
+			InodesTotal:       d.InodesTotal,
+			InodesUsedPercent: d.InodesUsedPercent,
+			MountPoint:        d.MountPoint,
+			FSType:            d.FSType,
+			SameDevicePaths:   d.Paths,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// buildLimitsSummary aggregates container limits; nil when the host capacity is unknown.
+func buildLimitsSummary(v0 *types.Report) *LimitsSummary {
+	t := rules.ComputeLimitTotals(v0)
+	if t.HostMemoryBytes == 0 && t.HostCPUs == 0 {
+		return nil
+	}
+	return &LimitsSummary{
+		MemoryLimitBytes:             t.MemoryLimitBytes,
+		HostMemoryBytes:              t.HostMemoryBytes,
+		MemoryOvercommitRatio:        t.MemoryRatio,
+		CPULimitCores:                t.CPULimitCores,
+		HostCPUs:                     t.HostCPUs,
+		CPUOvercommitRatio:           t.CPURatio,
+		ContainersWithoutMemoryLimit: t.WithoutMemoryLimit,
+		ContainersWithoutCPULimit:    t.WithoutCPULimit,
+	}
+}
+
+// cleanupPruneUntil matches the builder_prune action of DOCKER_STORAGE_BLOAT.
+const cleanupPruneUntil = 168 * time.Hour
+
+// buildCleanupPlan simulates each prune operation on its own; nil without df data.
+func buildCleanupPlan(df *facts.DockerSystemDfSummary, now time.Time) *CleanupPlan {
+	if df == nil {
+		return nil
 	}
+	cp := &CleanupPlan{BuilderPruneUntil: cleanupPruneUntil.String(), Operations: []CleanupOperation{}}
+	for _, op := range plan.Operations {
+		for _, s := range plan.Simulate(df, []string{op}, cleanupPruneUntil, now).Steps {
+			cp.Operations = append(cp.Operations, CleanupOperation{
+				Name:             s.Name,
+				Command:          s.Command,
+				Risk:             s.Risk,
+				Description:      s.Description,
+				Objects:          s.Objects,
+				ReclaimableBytes: s.ReclaimableBytes,
+			})
+		}
+	}
+	return cp
 }
 
 func newScanID(t time.Time) string {
@@ -216,16 +337,20 @@ func mapIssueToFinding(is types.Issue) Finding {
 
 	category := "general"
 	switch is.RuleID {
-	case "DISK_USAGE_HIGH":
+	case "DISK_USAGE_HIGH", "INODE_USAGE_HIGH", "HOST_MEMORY_PRESSURE", "HOST_SWAP_HEAVY", "HOST_LOAD_HIGH", "SYSCTL_NOT_RECOMMENDED", "KERNEL_TOO_OLD", "CGROUP_V1", "CGROUP_CONTROLLERS_MISSING":
 		category = "host"
-	case "DOCKER_STORAGE_BLOAT", "LOG_BLOAT", "VOLUME_BLOAT", "VOLUME_SIZE_HIGH":
+	case "DOCKER_STORAGE_BLOAT", "BUILD_CACHE_BLOAT", "IMAGE_DANGLING", "IMAGE_UNUSED", "IMAGE_VERSIONS_PILEUP", "CONTAINER_WRITABLE_LAYER_LARGE", "LOG_BLOAT", "LOG_ROTATION_MISSING", "VOLUME_BLOAT", "VOLUME_SIZE_HIGH":
 		category = "storage"
 	case "RESTART_LOOP", "OOM_KILLED", "HEALTHCHECK_UNHEALTHY":
 		category = "stability"
-	case "NETWORK_OVERLAP":
+	case "CPU_THROTTLED", "MEMORY_NEAR_LIMIT", "PIDS_NEAR_LIMIT":
+		category = "performance"
+	case "NETWORK_OVERLAP", "PORT_CONFLICT":
 		category = "networking"
-	case "DAEMON_RISKY_SETTINGS":
+	case "DAEMON_RISKY_SETTINGS", "DAEMON_WARNINGS", "STORAGE_DRIVER_UNSUPPORTED", "ENGINE_VERSION_OUTDATED", "CGROUP_DRIVER_MISMATCH", "DAEMON_CONFIG_INVALID", "DAEMON_TCP_WITHOUT_TLS", "DAEMON_LOG_ROTATION_MISSING", "DAEMON_LIVE_RESTORE_DISABLED", "DAEMON_USERNS_REMAP_DISABLED", "DAEMON_ICC_ENABLED", "DAEMON_DEFAULT_ULIMITS_UNSET", "DAEMON_ADDRESS_POOL_OVERLAP", "CONTAINER_NO_MEMORY_LIMIT", "CONTAINER_NO_PIDS_LIMIT", "CONTAINER_SWAP_UNLIMITED", "LIMITS_OVERCOMMIT":
 		category = "configuration"
+	case "CONTAINER_PRIVILEGED", "CONTAINER_DANGEROUS_CAPABILITIES", "CONTAINER_DOCKER_SOCKET_MOUNTED", "CONTAINER_HOST_NETWORK", "CONTAINER_HOST_PID", "CONTAINER_HOST_IPC", "CONTAINER_SECCOMP_DISABLED", "CONTAINER_APPARMOR_DISABLED", "CONTAINER_ROOTFS_WRITABLE", "CONTAINER_RUNS_AS_ROOT", "SECRET_IN_ENV", "PORT_EXPOSED_PUBLIC":
+		category = "security"
 	}
 
 	title := is.RuleID
@@ -234,6 +359,50 @@ func mapIssueToFinding(is types.Issue) Finding {
 		title = "Disk usage is above threshold"
 	case "DOCKER_STORAGE_BLOAT":
 		title = "Docker storage usage is high"
+	case "BUILD_CACHE_BLOAT":
+		title = "Build cache is large"
+	case "IMAGE_DANGLING":
+		title = "Dangling images detected"
+	case "IMAGE_UNUSED":
+		title = "Old images unused by any container"
+	case "IMAGE_VERSIONS_PILEUP":
+		title = "Image versions are piling up"
+	case "CONTAINER_WRITABLE_LAYER_LARGE":
+		title = "Container writable layer is large"
+	case "CPU_THROTTLED":
+		title = "Container is CPU throttled"
+	case "MEMORY_NEAR_LIMIT":
+		title = "Container memory is near its limit"
+	case "PIDS_NEAR_LIMIT":
+		title = "Container PIDs are near their limit"
+	case "CONTAINER_NO_MEMORY_LIMIT":
+		title = "Container has no memory limit"
+	case "CONTAINER_NO_PIDS_LIMIT":
+		title = "Container has no PIDs limit"
+	case "CONTAINER_SWAP_UNLIMITED":
+		title = "Container swap is unlimited"
+	case "LIMITS_OVERCOMMIT":
+		title = "Container limits overcommit the host"
+	case "INODE_USAGE_HIGH":
+		title = "Filesystem is running out of inodes"
+	case "HOST_MEMORY_PRESSURE":
+		title = "Host memory is under pressure"
+	case "HOST_SWAP_HEAVY":
+		title = "Host swap usage is high"
+	case "HOST_LOAD_HIGH":
+		title = "Host load is high"
+	case "SYSCTL_NOT_RECOMMENDED":
+		title = "Kernel parameter is below the recommended value"
+	case "KERNEL_TOO_OLD":
+		title = "Kernel is too old for this Docker setup"
+	case "LOG_ROTATION_MISSING":
+		title = "Container log rotation is not configured"
+	case "CGROUP_V1":
+		title = "Host runs cgroup v1"
+	case "CGROUP_DRIVER_MISMATCH":
+		title = "Docker uses the cgroupfs driver on a systemd host"
+	case "CGROUP_CONTROLLERS_MISSING":
+		title = "cgroup controllers are missing; resource limits are not enforced"
 	case "RESTART_LOOP":
 		title = "Container is restarting frequently"
 	case "OOM_KILLED":
@@ -250,6 +419,54 @@ func mapIssueToFinding(is types.Issue) Finding {
 		title = "Docker network CIDRs overlap"
 	case "DAEMON_RISKY_SETTINGS":
 		title = "Docker daemon has risky settings"
+	case "DAEMON_WARNINGS":
+		title = "Docker daemon reports warnings"
+	case "STORAGE_DRIVER_UNSUPPORTED":
+		title = "Storage driver setup is deprecated or unsupported"
+	case "ENGINE_VERSION_OUTDATED":
+		title = "Docker Engine version is outdated"
+	case "DAEMON_CONFIG_INVALID":
+		title = "Docker daemon configuration is invalid"
+	case "DAEMON_TCP_WITHOUT_TLS":
+		title = "Docker API is exposed over TCP without TLS"
+	case "DAEMON_LOG_ROTATION_MISSING":
+		title = "Default container log rotation is not configured"
+	case "DAEMON_LIVE_RESTORE_DISABLED":
+		title = "Docker live-restore is disabled"
+	case "DAEMON_USERNS_REMAP_DISABLED":
+		title = "User namespace remapping is disabled"
+	case "DAEMON_ICC_ENABLED":
+		title = "Inter-container communication is enabled"
+	case "DAEMON_DEFAULT_ULIMITS_UNSET":
+		title = "Default container ulimits are not configured"
+	case "DAEMON_ADDRESS_POOL_OVERLAP":
+		title = "Docker address pools collide with host routes"
+	case "CONTAINER_PRIVILEGED":
+		title = "Container runs privileged"
+	case "CONTAINER_DANGEROUS_CAPABILITIES":
+		title = "Container adds dangerous capabilities"
+	case "CONTAINER_DOCKER_SOCKET_MOUNTED":
+		title = "Docker socket is mounted into a container"
+	case "CONTAINER_HOST_NETWORK":
+		title = "Container shares the host network namespace"
+	case "CONTAINER_HOST_PID":
+		title = "Container shares the host PID namespace"
+	case "CONTAINER_HOST_IPC":
+		title = "Container shares the host IPC namespace"
+	case "CONTAINER_SECCOMP_DISABLED":
+		title = "Container seccomp profile is disabled"
+	case "CONTAINER_APPARMOR_DISABLED":
+		title = "Container AppArmor profile is disabled"
+	case "CONTAINER_ROOTFS_WRITABLE":
+		title = "Container root filesystem is writable"
+	case "CONTAINER_RUNS_AS_ROOT":
+		title = "Container runs as root"
+	case "SECRET_IN_ENV":
+		title = "Secrets passed as environment variables"
+	case "PORT_EXPOSED_PUBLIC":
+		title = "Ports are published on all interfaces"
+	case "PORT_CONFLICT":
+		title = "Host port is claimed more than once"
 	}
 
 	scope := Scope{}
@@ -259,6 +476,9 @@ func mapIssueToFinding(is types.Issue) Finding {
 	if strings.HasPrefix(is.Subject, "path=") {
 		scope.Path = strings.TrimPrefix(is.Subject, "path=")
 	}
+	if strings.HasPrefix(is.Subject, "repository=") {
+		scope.Image = strings.TrimPrefix(is.Subject, "repository=")
+	}
 	if v, ok := is.Facts["container_name"]; ok {
 		if s, ok := v.(string); ok {
 			scope.ContainerName = s
@@ -285,6 +505,14 @@ func mapIssueToFinding(is types.Issue) Finding {
 		Notes:    notes,
 	}
 
+	recos := []Recommendation{reco}
+	recos = append(recos, actionRecommendations(is.Actions)...)
+
+	refs := make([]Reference, 0, len(is.References))
+	for _, r := range is.References {
+		refs = append(refs, Reference{Kind: r.Kind, Label: r.Label, URL: r.URL})
+	}
+
 	fp := is.RuleID
 	if strings.TrimSpace(is.Subject) != "" {
 		fp += ":" + is.Subject
@@ -294,9 +522,9 @@ func mapIssueToFinding(is types.Issue) Finding {
 
 	confidence := "medium"
 	switch is.RuleID {
-	case "DISK_USAGE_HIGH", "VOLUME_SIZE_HIGH", "LOG_BLOAT":
+	case "DISK_USAGE_HIGH", "INODE_USAGE_HIGH", "VOLUME_SIZE_HIGH", "LOG_BLOAT", "HOST_MEMORY_PRESSURE", "HOST_SWAP_HEAVY", "HOST_LOAD_HIGH", "SYSCTL_NOT_RECOMMENDED", "KERNEL_TOO_OLD", "DAEMON_CONFIG_INVALID", "DAEMON_TCP_WITHOUT_TLS", "DAEMON_LOG_ROTATION_MISSING", "DAEMON_LIVE_RESTORE_DISABLED", "DAEMON_USERNS_REMAP_DISABLED", "DAEMON_ICC_ENABLED", "DAEMON_DEFAULT_ULIMITS_UNSET", "DAEMON_ADDRESS_POOL_OVERLAP":
 		confidence = "high" // Relies on host FS access
-	case "DOCKER_STORAGE_BLOAT", "RESTART_LOOP", "OOM_KILLED", "HEALTHCHECK_UNHEALTHY", "VOLUME_BLOAT", "NETWORK_OVERLAP", "DAEMON_RISKY_SETTINGS":
+	case "DOCKER_STORAGE_BLOAT", "BUILD_CACHE_BLOAT", "IMAGE_DANGLING", "IMAGE_UNUSED", "IMAGE_VERSIONS_PILEUP", "CONTAINER_WRITABLE_LAYER_LARGE", "CPU_THROTTLED", "MEMORY_NEAR_LIMIT", "PIDS_NEAR_LIMIT", "CONTAINER_NO_MEMORY_LIMIT", "CONTAINER_NO_PIDS_LIMIT", "CONTAINER_SWAP_UNLIMITED", "LIMITS_OVERCOMMIT", "RESTART_LOOP", "OOM_KILLED", "HEALTHCHECK_UNHEALTHY", "VOLUME_BLOAT", "NETWORK_OVERLAP", "PORT_CONFLICT", "DAEMON_RISKY_SETTINGS", "DAEMON_WARNINGS", "STORAGE_DRIVER_UNSUPPORTED", "ENGINE_VERSION_OUTDATED", "CGROUP_V1", "CGROUP_DRIVER_MISMATCH", "CGROUP_CONTROLLERS_MISSING", "LOG_ROTATION_MISSING", "CONTAINER_PRIVILEGED", "CONTAINER_DANGEROUS_CAPABILITIES", "CONTAINER_DOCKER_SOCKET_MOUNTED", "CONTAINER_HOST_NETWORK", "CONTAINER_HOST_PID", "CONTAINER_HOST_IPC", "CONTAINER_SECCOMP_DISABLED", "CONTAINER_APPARMOR_DISABLED", "CONTAINER_ROOTFS_WRITABLE", "CONTAINER_RUNS_AS_ROOT", "SECRET_IN_ENV", "PORT_EXPOSED_PUBLIC":
 		confidence = "medium" // API-based
 	default:
 		confidence = "low"
@@ -312,8 +540,65 @@ func mapIssueToFinding(is types.Issue) Finding {
 		Summary:         is.Description,
 		Scope:           scope,
 		Evidence:        evidence,
-		Recommendations: []Recommendation{reco},
-		References:      []Reference{},
+		Recommendations: recos,
+		References:      refs,
 	}
 }
 
+
+// actionRecommendations groups typed actions into one recommendation per risk level,
+// ordered safe, planned, risky.
+func actionRecommendations(actions []types.Action) []Recommendation {
+	byRisk := map[string][]Action{}
+	for _, a := range actions {
+		risk := a.Risk
+		if risk == "" {
+			risk = "planned"
+		}
+		byRisk[risk] = append(byRisk[risk], Action{Type: a.Type, Target: a.Target, Params: a.Params})
+	}
+	var out []Recommendation
+	for _, risk := range []string{"safe", "planned", "risky"} {
+		acts := byRisk[risk]
+		if len(acts) == 0 {
+			continue
+		}
+		commands := make([]string, 0, len(acts))
+		for _, a := range acts {
+			commands = append(commands, ActionCommand(a))
+		}
+		out = append(out, Recommendation{
+			Risk:     risk,
+			Title:    "Automated remediation (" + risk + ")",
+			Steps:    []string{"Review, then apply with 'docker-doctor fix --scan scan.json --allow " + risk + " --apply'"},
+			Commands: commands,
+			Notes:    []string{},
+			Actions:  acts,
+		})
+	}
+	return out
+}
+
+// ActionCommand returns the docker CLI equivalent of an action, for display.
+func ActionCommand(a Action) string {
+	switch a.Type {
+	case "image_remove":
+		return "docker image rm " + a.Target
+	case "builder_prune":
+		if until := a.Params["until"]; until != "" {
+			return "docker builder prune -f --filter until=" + until
+		}
+		return "docker builder prune -f"
+	case "volume_remove":
+		return "docker volume rm " + a.Target
+	case "log_truncate":
+		return "truncate -s 0 $(docker inspect --format '{{.LogPath}}' " + a.Target + ")"
+	}
+	return a.Type + " " + a.Target
+}
+
+// FindingFromIssue maps a single v0 issue onto a v1 finding. It is used by callers
+// that produce issues outside a full scan (e.g. watch mode).
+func FindingFromIssue(is types.Issue) Finding {
+	return mapIssueToFinding(is)
+}
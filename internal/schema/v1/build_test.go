@@ -62,3 +62,23 @@ func TestBuildFromV0_HasRequiredTopLevelFields(t *testing.T) {
 	}
 }
 
+
+func TestBuildFromV0_ReportsUnsampledContainers(t *testing.T) {
+	v0 := &types.Report{
+		Issues:          []types.Issue{},
+		Timestamp:       time.Now(),
+		CollectorErrors: map[string][]string{"container_stats": {"web (abc123): no stats received within the sampling window"}},
+	}
+	cfg := &config.Config{Scan: config.ScanConfig{Mode: "basic", Timeout: 30, DockerHost: "unix:///nonexistent.sock", Sample: 10 * time.Second}}
+
+	r := BuildFromV0(context.Background(), v0, cfg, "1.41", time.Now().Add(-time.Second), time.Now(), "dev", "", "")
+	for _, c := range r.Collectors {
+		if c.Name == "container_stats" {
+			if c.Status != "error" || len(c.Errors) != 1 {
+				t.Fatalf("expected the unsampled container as an error, got %+v", c)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected a container_stats collector, got %+v", r.Collectors)
+}
@@ -12,6 +12,36 @@ type Report struct {
 	Findings      []Finding   `json:"findings"`
 	Errors        []string    `json:"errors"`
 	Raw           Raw         `json:"raw"`
+	CleanupPlan   *CleanupPlan `json:"cleanupPlan,omitempty"`
+	Inventory     *Inventory   `json:"inventory,omitempty"`
+}
+
+// Inventory lists per-container details that are useful next to the findings.
+type Inventory struct {
+	PublishedPorts []PublishedPort `json:"publishedPorts,omitempty"`
+	LogPolicies    []LogPolicy     `json:"logPolicies,omitempty"`
+}
+
+// LogPolicy is the effective log driver and retention of a container.
+type LogPolicy struct {
+	ContainerID   string `json:"containerId"`
+	ContainerName string `json:"containerName"`
+	Driver        string `json:"driver"`
+	Policy        string `json:"policy"`       // e.g. "10m x 3 files", "unbounded"
+	Bounded       bool   `json:"bounded"`      // log files on the host cannot grow without limit
+	LogSizeBytes  uint64 `json:"logSizeBytes"` // 0 when not measured
+}
+
+// PublishedPort is a container port published on the host.
+type PublishedPort struct {
+	ContainerID   string `json:"containerId"`
+	ContainerName string `json:"containerName"`
+	HostIP        string `json:"hostIp"`
+	HostPort      int    `json:"hostPort"`
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol"`
+	Public        bool   `json:"public"`            // published on all interfaces
+	Service       string `json:"service,omitempty"` // well-known sensitive service on this port
 }
 
 type Tool struct {
@@ -67,6 +97,7 @@ type TargetDocker struct {
 	APIVersion     string `json:"apiVersion"`
 	StorageDriver  string `json:"storageDriver"`
 	CgroupVersion  string `json:"cgroupVersion"`
+	CgroupDriver   string `json:"cgroupDriver,omitempty"`
 	DataRoot       string `json:"dataRoot"`
 }
 
@@ -93,6 +124,35 @@ type SummaryCounts struct {
 
 type SummaryResourceSnapshot struct {
 	DockerSystemDf DockerSystemDf `json:"dockerSystemDf"`
+	Limits         *LimitsSummary `json:"limits,omitempty"`
+	Filesystems    []Filesystem   `json:"filesystems,omitempty"`
+}
+
+// Filesystem is the byte and inode usage of a monitored host path.
+type Filesystem struct {
+	Path              string   `json:"path"`
+	UsedBytes         uint64   `json:"usedBytes"`
+	TotalBytes        uint64   `json:"totalBytes"`
+	UsedPercent       float64  `json:"usedPercent"`
+	InodesUsed        uint64   `json:"inodesUsed"`
+	InodesTotal       uint64   `json:"inodesTotal"` // 0 when the filesystem does not report inodes
+	InodesUsedPercent float64  `json:"inodesUsedPercent"`
+	MountPoint        string   `json:"mountPoint,omitempty"`
+	FSType            string   `json:"fsType,omitempty"`
+	SameDevicePaths   []string `json:"sameDevicePaths,omitempty"` // other monitored paths on this filesystem
+}
+
+// LimitsSummary compares the summed limits of running containers with host capacity.
+// A ratio above 1 means the limits overcommit the host.
+type LimitsSummary struct {
+	MemoryLimitBytes             uint64  `json:"memoryLimitBytes"`
+	HostMemoryBytes              uint64  `json:"hostMemoryBytes"`
+	MemoryOvercommitRatio        float64 `json:"memoryOvercommitRatio"`
+	CPULimitCores                float64 `json:"cpuLimitCores"`
+	HostCPUs                     int     `json:"hostCpus"`
+	CPUOvercommitRatio           float64 `json:"cpuOvercommitRatio"`
+	ContainersWithoutMemoryLimit int     `json:"containersWithoutMemoryLimit"`
+	ContainersWithoutCPULimit    int     `json:"containersWithoutCpuLimit"`
 }
 
 type DockerSystemDf struct {
@@ -141,6 +201,30 @@ type Recommendation struct {
 	Steps    []string `json:"steps"`
 	Commands []string `json:"commands"`
 	Notes    []string `json:"notes"`
+	Actions  []Action `json:"actions,omitempty"`
+}
+
+// Action is a typed, machine-executable form of a recommendation (see `docker-doctor fix`).
+type Action struct {
+	Type   string            `json:"type"` // image_remove | builder_prune | volume_remove | log_truncate
+	Target string            `json:"target,omitempty"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// CleanupPlan lists how much space each Docker prune operation would free on its own,
+// simulated from the `/system/df` payload (see `docker-doctor plan`).
+type CleanupPlan struct {
+	BuilderPruneUntil string             `json:"builderPruneUntil"`
+	Operations        []CleanupOperation `json:"operations"`
+}
+
+type CleanupOperation struct {
+	Name             string `json:"name"`
+	Command          string `json:"command"`
+	Risk             string `json:"risk"` // safe | planned | risky
+	Description      string `json:"description"`
+	Objects          int    `json:"objects"`
+	ReclaimableBytes uint64 `json:"reclaimableBytes"`
 }
 
 type Reference struct {
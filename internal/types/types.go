@@ -4,40 +4,250 @@ import "time"
 
 // DiskInfo holds disk usage information.
 type DiskInfo struct {
-	Used        uint64  `json:"used"`
-	Total       uint64  `json:"total"`
-	UsedPercent float64 `json:"used_percent"`
+	Used              uint64   `json:"used"`
+	Total             uint64   `json:"total"`
+	UsedPercent       float64  `json:"used_percent"`
+	InodesUsed        uint64   `json:"inodes_used"`
+	InodesTotal       uint64   `json:"inodes_total"` // 0 when the filesystem does not report inodes (e.g. btrfs)
+	InodesUsedPercent float64  `json:"inodes_used_percent"`
+	MountPoint        string   `json:"mount_point,omitempty"` // backing mount point from /proc/self/mountinfo
+	FSType            string   `json:"fs_type,omitempty"`
+	Paths             []string `json:"paths,omitempty"` // other monitored paths on the same device
 }
 
 // HostInfo holds basic host system information and disk usage.
 type HostInfo struct {
-	HostID      string                `json:"host_id"`
-	Hostname    string                `json:"hostname"`
-	OS          string                `json:"os"`
-	Arch        string                `json:"arch"`
-	Kernel      string                `json:"kernel"`
-	UptimeSeconds int64               `json:"uptime_seconds"`
-	DiskUsage   map[string]*DiskInfo `json:"disk_usage"` // path to disk info
+	HostID        string               `json:"host_id"`
+	Hostname      string               `json:"hostname"`
+	OS            string               `json:"os"`
+	Arch          string               `json:"arch"`
+	Kernel        string               `json:"kernel"`
+	UptimeSeconds int64                `json:"uptime_seconds"`
+	DiskUsage     map[string]*DiskInfo `json:"disk_usage"`          // path to disk info
+	Resources     *HostResources       `json:"resources,omitempty"` // nil when /proc is not readable
+	Listeners     []Listener           `json:"listeners,omitempty"` // listening sockets of the scanner's network namespace
+	Routes        []Route              `json:"routes,omitempty"`    // IPv4 routes from /proc/net/route (full mode)
+	Sysctls       map[string]string    `json:"sysctls,omitempty"`   // /proc/sys values by dotted name (e.g. "vm.max_map_count")
+	Cgroup        *HostCgroup          `json:"cgroup,omitempty"`    // from /sys/fs/cgroup; nil when not mounted
+}
+
+// HostCgroup describes the cgroup hierarchy seen by the scanner.
+type HostCgroup struct {
+	Version     string   `json:"version"`     // "1" | "2"
+	Controllers []string `json:"controllers"` // enabled controllers (cgroup.controllers on v2, /proc/cgroups on v1)
+	Systemd     bool     `json:"systemd"`     // host booted with systemd
+}
+
+// Route is an IPv4 route of the host.
+type Route struct {
+	Iface       string `json:"iface"`
+	Destination string `json:"destination"` // CIDR
+}
+
+// Listener is a listening TCP socket or bound UDP socket from /proc/net.
+type Listener struct {
+	Protocol string `json:"protocol"` // tcp | udp
+	Address  string `json:"address"`  // "0.0.0.0" or "::" for all interfaces
+	Port     int    `json:"port"`
+}
+
+// HostResources holds host memory, swap, load and pressure read from /proc.
+type HostResources struct {
+	MemTotal     uint64    `json:"mem_total"` // bytes
+	MemAvailable uint64    `json:"mem_available"`
+	SwapTotal    uint64    `json:"swap_total"`
+	SwapFree     uint64    `json:"swap_free"`
+	Load1        float64   `json:"load1"`
+	Load5        float64   `json:"load5"`
+	Load15       float64   `json:"load15"`
+	NCPU         int       `json:"ncpu"`
+	PSICPU       *Pressure `json:"psi_cpu,omitempty"` // nil without PSI (kernel < 4.20 or psi=0)
+	PSIMemory    *Pressure `json:"psi_memory,omitempty"`
+	PSIIO        *Pressure `json:"psi_io,omitempty"`
+	OOMKills     uint64    `json:"oom_kills"` // kernel OOM kills since boot (vmstat oom_kill)
+	PswpIn       uint64    `json:"pswpin"`    // pages swapped in since boot
+	PswpOut      uint64    `json:"pswpout"`   // pages swapped out since boot
+}
+
+// Pressure is one PSI resource: the share of time (percent) some or all tasks stalled.
+type Pressure struct {
+	SomeAvg10  float64 `json:"some_avg10"`
+	SomeAvg60  float64 `json:"some_avg60"`
+	SomeAvg300 float64 `json:"some_avg300"`
+	FullAvg10  float64 `json:"full_avg10"`
+	FullAvg60  float64 `json:"full_avg60"`
+	FullAvg300 float64 `json:"full_avg300"`
 }
 
 // DockerInfo holds Docker daemon and version information.
 type DockerInfo struct {
 	Version       string                 `json:"version"`
-	CgroupVersion string                 `json:"cgroup_version"`
+	CgroupVersion string                 `json:"cgroup_version"`          // "1" | "2"; empty when unknown
+	CgroupDriver  string                 `json:"cgroup_driver,omitempty"` // cgroupfs | systemd | none
+	CgroupSource  string                 `json:"cgroup_source,omitempty"` // api | host (fallback from /sys/fs/cgroup)
+	LimitSupport  *LimitSupport          `json:"limit_support,omitempty"` // resource limits the daemon can enforce, from /info
 	DataRoot      string                 `json:"data_root"`
 	DaemonInfo    map[string]interface{} `json:"daemon_info"`
+	MemTotal      int64                  `json:"mem_total"` // host memory visible to the daemon, in bytes
+	NCPU          int                    `json:"ncpu"`
+	DaemonConfig  *DaemonConfig          `json:"daemon_config,omitempty"` // full mode only
+	Warnings      []string               `json:"warnings,omitempty"`      // from /info, "WARNING: " prefix removed
+	DriverStatus  map[string]string      `json:"driver_status,omitempty"` // storage driver status from /info, e.g. "Supports d_type"
+}
+
+// LimitSupport reports which resource limits the daemon can enforce (cgroup controllers
+// available to it), as returned by /info.
+type LimitSupport struct {
+	Memory    bool `json:"memory"`
+	Swap      bool `json:"swap"`
+	Pids      bool `json:"pids"`
+	CPUQuota  bool `json:"cpu_quota"`
+	CPUShares bool `json:"cpu_shares"`
+	CPUSet    bool `json:"cpuset"`
+}
+
+// DaemonConfig is the dockerd configuration read from daemon.json and the dockerd
+// command line. Only settings the rules check are kept.
+type DaemonConfig struct {
+	Path           string            `json:"path"`
+	Source         string            `json:"source"` // default | cmdline (--config-file)
+	Exists         bool              `json:"exists"`
+	Readable       bool              `json:"readable"` // the effective configuration is known
+	ParseError     string            `json:"parse_error,omitempty"`
+	DockerdFound   bool              `json:"dockerd_found"`  // the dockerd process was visible in /proc
+	Keys           []string          `json:"keys,omitempty"` // top-level keys of daemon.json
+	Hosts          []string          `json:"hosts,omitempty"`
+	TLS            bool              `json:"tls"`
+	TLSVerify      bool              `json:"tlsverify"`
+	LogDriver      string            `json:"log_driver,omitempty"`
+	LogOpts        map[string]string `json:"log_opts,omitempty"`
+	LiveRestore    bool              `json:"live_restore"`
+	UsernsRemap    string            `json:"userns_remap,omitempty"`
+	ICC            *bool             `json:"icc,omitempty"` // nil when unset (defaults to true)
+	DefaultUlimits []string          `json:"default_ulimits,omitempty"`
+	Bip            string            `json:"bip,omitempty"`
+	AddressPools   []AddressPool     `json:"default_address_pools,omitempty"`
+}
+
+// AddressPool is an entry of default-address-pools.
+type AddressPool struct {
+	Base string `json:"base"`
+	Size int    `json:"size"`
 }
 
 // ContainerInfo holds information about a container.
 type ContainerInfo struct {
-	ID               string    `json:"id"`
-	Name             string    `json:"name"`
-	RestartCount     int       `json:"restart_count"`
-	Status           string    `json:"status"`
-	OOMKilled        bool      `json:"oom_killed"`
-	HealthStatus     string    `json:"health_status"`
-	UnhealthySince   time.Time `json:"unhealthy_since"`
-	LogSize          uint64    `json:"log_size"` // estimated log size in bytes
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	RestartCount     int               `json:"restart_count"`
+	Status           string            `json:"status"`
+	OOMKilled        bool              `json:"oom_killed"`
+	HealthStatus     string            `json:"health_status"`
+	UnhealthySince   time.Time         `json:"unhealthy_since"`
+	LogSize          uint64            `json:"log_size"` // estimated log size in bytes
+	ImageID          string            `json:"image_id,omitempty"`
+	SizeRw           int64             `json:"size_rw"` // writable layer size in bytes (from /system/df), 0 when unknown
+	WritableTopPaths []PathUsage       `json:"writable_top_paths,omitempty"`
+	Stats            *ResourceSample   `json:"stats,omitempty"`  // set when the scan sampled container stats
+	Limits           *ResourceLimits   `json:"limits,omitempty"` // from inspect HostConfig, nil when inspect failed
+	Mounts           []Mount           `json:"mounts,omitempty"`
+	Security         *SecurityInfo     `json:"security,omitempty"`         // from inspect, nil when inspect failed
+	SensitiveEnv     []SensitiveEnvVar `json:"sensitive_env,omitempty"`    // secret-looking env vars; values are never stored
+	Ports            []PortBinding     `json:"ports,omitempty"`            // published ports of a running container
+	ConfiguredPorts  []PortBinding     `json:"configured_ports,omitempty"` // HostConfig port bindings with a fixed host port, claimed on (re)start
+	LogConfig        *LogConfig        `json:"log_config,omitempty"`       // from inspect HostConfig, nil when inspect failed
+}
+
+// LogConfig is the logging driver and options of a container. The daemon merges its
+// default log-opts in at creation time, so this is the effective configuration.
+type LogConfig struct {
+	Driver string            `json:"driver"`
+	Opts   map[string]string `json:"opts,omitempty"`
+}
+
+// SecurityInfo holds the security-relevant settings of a container.
+type SecurityInfo struct {
+	Privileged      bool     `json:"privileged"`
+	CapAdd          []string `json:"cap_add,omitempty"`
+	NetworkMode     string   `json:"network_mode,omitempty"`
+	PidMode         string   `json:"pid_mode,omitempty"`
+	IpcMode         string   `json:"ipc_mode,omitempty"`
+	SecurityOpt     []string `json:"security_opt,omitempty"`
+	AppArmorProfile string   `json:"apparmor_profile,omitempty"`
+	ReadonlyRootfs  bool     `json:"readonly_rootfs"`
+	User            string   `json:"user"` // effective user from the container config, "" means root
+}
+
+// SensitiveEnvVar is an environment variable that looks like a secret. Only the name and
+// a keyed fingerprint of the value are kept. The fingerprint lets reports show that two
+// containers share a value; without the key it cannot be brute-forced back to the value.
+// It does reveal reuse, and anyone holding the key can test guesses against it.
+type SensitiveEnvVar struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`                 // password, token, api_key, jwt, private_key, ...
+	MatchedBy string `json:"matched_by"`           // name | value
+	ValueHMAC string `json:"value_hmac,omitempty"` // first 16 hex chars of HMAC-SHA256(key, value)
+}
+
+// PortBinding is a container port published on the host.
+type PortBinding struct {
+	HostIP        string `json:"host_ip"` // "0.0.0.0" or "::" for all interfaces
+	HostPort      int    `json:"host_port"`
+	ContainerPort int    `json:"container_port"`
+	Protocol      string `json:"protocol"` // tcp | udp | sctp
+}
+
+// Mount is a bind mount, volume or tmpfs of a container.
+type Mount struct {
+	Type        string `json:"type"` // bind | volume | tmpfs | npipe
+	Source      string `json:"source,omitempty"`
+	Destination string `json:"destination"`
+	Name        string `json:"name,omitempty"` // volume name
+	RW          bool   `json:"rw"`
+}
+
+// ResourceLimits holds the resource limits of a container's HostConfig.
+// Zero means "not set" for every field; MemorySwap is -1 when swap is unlimited.
+type ResourceLimits struct {
+	Memory     int64    `json:"memory"`
+	MemorySwap int64    `json:"memory_swap"`
+	NanoCPUs   int64    `json:"nano_cpus"`
+	CPUQuota   int64    `json:"cpu_quota"`
+	CPUPeriod  int64    `json:"cpu_period"`
+	CPUShares  int64    `json:"cpu_shares"`
+	PidsLimit  int64    `json:"pids_limit"` // 0 or -1 when unlimited
+	Ulimits    []Ulimit `json:"ulimits,omitempty"`
+}
+
+// Ulimit is a per-container ulimit override (e.g. nofile, nproc).
+type Ulimit struct {
+	Name string `json:"name"`
+	Soft int64  `json:"soft"`
+	Hard int64  `json:"hard"`
+}
+
+// ResourceSample summarises the stats streamed for a running container during a scan.
+type ResourceSample struct {
+	DurationSeconds  float64 `json:"duration_seconds"`
+	Samples          int     `json:"samples"`
+	CPUPercent       float64 `json:"cpu_percent"`       // average over the window, 100 = one full core
+	CPUPercentMax    float64 `json:"cpu_percent_max"`   // highest between consecutive samples
+	CPULimitCores    float64 `json:"cpu_limit_cores"`   // from --cpus or --cpu-quota/--cpu-period, 0 when unlimited
+	Periods          uint64  `json:"periods"`           // CFS periods elapsed during the window
+	ThrottledPeriods uint64  `json:"throttled_periods"` // CFS periods throttled during the window
+	ThrottledRatio   float64 `json:"throttled_ratio"`
+	MemoryUsage      uint64  `json:"memory_usage"` // highest usage excluding inactive page cache
+	MemoryLimit      uint64  `json:"memory_limit"`
+	MemoryLimitSet   bool    `json:"memory_limit_set"` // false when the limit is the host memory
+	PidsCurrent      uint64  `json:"pids_current"`
+	PidsLimit        uint64  `json:"pids_limit"` // 0 when unlimited
+}
+
+// PathUsage summarises changes below a path of a container's writable layer.
+type PathUsage struct {
+	Path    string `json:"path"`
+	Bytes   uint64 `json:"bytes"`   // 0 when the layer is not readable from the host
+	Changes int    `json:"changes"` // added or modified entries
 }
 
 // Containers holds container count and detailed list.
@@ -48,8 +258,14 @@ type Containers struct {
 
 // ImageInfo holds information about an image.
 type ImageInfo struct {
-	ID   string `json:"id"`
-	Size uint64 `json:"size"`
+	ID          string    `json:"id"`
+	Size        uint64    `json:"size"`
+	RepoTags    []string  `json:"repo_tags,omitempty"`
+	RepoDigests []string  `json:"repo_digests,omitempty"`
+	ParentID    string    `json:"parent_id,omitempty"`
+	Created     time.Time `json:"created"`
+	Containers  int64     `json:"containers"`  // containers using the image, -1 when not computed
+	SharedSize  int64     `json:"shared_size"` // bytes shared with other images, -1 when not computed
 }
 
 // Images holds image count and detailed list.
@@ -94,16 +310,36 @@ type Issue struct {
 	Description string                 `json:"description"`
 	Facts       map[string]interface{} `json:"facts"`
 	Solutions   []string               `json:"solutions"`
+	Actions     []Action               `json:"actions,omitempty"` // typed remediation steps (see fix command)
+	References  []Reference            `json:"references,omitempty"`
+}
+
+// Reference points to external guidance for an issue (e.g. a CIS Docker Benchmark control).
+type Reference struct {
+	Kind  string `json:"kind"` // cis | docs | advisory
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// Action is a typed remediation step a rule proposes for an issue.
+// The fix command executes it through the Docker API.
+type Action struct {
+	Type   string            `json:"type"`             // image_remove | builder_prune | volume_remove | log_truncate
+	Target string            `json:"target,omitempty"` // image ID, volume name or container ID
+	Risk   string            `json:"risk"`             // safe | planned | risky
+	Params map[string]string `json:"params,omitempty"`
 }
 
 // Report is the top-level structure for the scan report.
 type Report struct {
 	Host       HostInfo   `json:"host"`
-	Docker     DockerInfo  `json:"docker"`
-	Containers Containers  `json:"containers"`
+	Docker     DockerInfo `json:"docker"`
+	Containers Containers `json:"containers"`
 	Images     Images     `json:"images"`
 	Volumes    Volumes    `json:"volumes"`
 	Networks   Networks   `json:"networks"`
 	Issues     []Issue    `json:"issues"`
 	Timestamp  time.Time  `json:"timestamp"`
+	// CollectorErrors lists, per best-effort collector, the objects it could not cover.
+	CollectorErrors map[string][]string `json:"collector_errors,omitempty"`
 }
\ No newline at end of file
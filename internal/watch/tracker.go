@@ -0,0 +1,251 @@
+package watch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/rules"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+// Event is a container lifecycle event relevant to per-container rules.
+type Event struct {
+	ContainerID string
+	Name        string
+	Kind        string // die | start | oom | restart | health_status
+	Detail      string // e.g. "unhealthy" for health_status
+	Time        time.Time
+}
+
+// KindFromAction maps a Docker event action ("die", "health_status: unhealthy", ...)
+// to an event kind and detail. ok is false for actions watch mode does not track.
+func KindFromAction(action string) (kind, detail string, ok bool) {
+	action = strings.TrimSpace(action)
+	if strings.HasPrefix(action, "health_status") {
+		return "health_status", strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(action, "health_status"), ":")), true
+	}
+	switch action {
+	case "die", "start", "oom", "restart":
+		return action, "", true
+	}
+	return "", "", false
+}
+
+// Tracker keeps per-container event counters over a sliding window and decides which
+// findings are new or changed since they were last emitted.
+type Tracker struct {
+	mu      sync.Mutex
+	window  time.Duration
+	cfg     *config.Config
+	events  map[string]map[string][]time.Time // container ID -> kind -> timestamps
+	names   map[string]string
+	emitted map[string]string // fingerprint -> severity
+}
+
+// NewTracker returns a Tracker with the given sliding window.
+func NewTracker(window time.Duration, cfg *config.Config) *Tracker {
+	return &Tracker{
+		window:  window,
+		cfg:     cfg,
+		events:  map[string]map[string][]time.Time{},
+		names:   map[string]string{},
+		emitted: map[string]string{},
+	}
+}
+
+// Observe records ev, re-evaluates the container's rules using info (the container's
+// current inspect state, may be nil if the container is gone) and returns only issues
+// that are new or whose severity changed since the last emission.
+func (t *Tracker) Observe(ev Event, info *types.ContainerInfo) []types.Issue {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := shortID(ev.ContainerID)
+	if ev.Name != "" {
+		t.names[id] = ev.Name
+	}
+	byKind, ok := t.events[id]
+	if !ok {
+		byKind = map[string][]time.Time{}
+		t.events[id] = byKind
+	}
+	byKind[ev.Kind] = append(byKind[ev.Kind], ev.Time)
+	t.prune(id, ev.Time)
+
+	var issues []types.Issue
+	if info != nil {
+		issues = rules.EvaluateContainer(*info, t.cfg)
+	}
+	issues = t.applyWindow(id, issues, info)
+	return t.filterEmitted(id, issues)
+}
+
+// Counts returns the number of events per kind inside the window for each container.
+func (t *Tracker) Counts(now time.Time) map[string]map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := map[string]map[string]int{}
+	for id := range t.events {
+		t.prune(id, now)
+		for kind, ts := range t.events[id] {
+			if len(ts) == 0 {
+				continue
+			}
+			if out[id] == nil {
+				out[id] = map[string]int{}
+			}
+			out[id][kind] = len(ts)
+		}
+	}
+	return out
+}
+
+// Name returns the last known name of a container.
+func (t *Tracker) Name(id string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.names[shortID(id)]
+}
+
+func (t *Tracker) prune(id string, now time.Time) {
+	cutoff := now.Add(-t.window)
+	for kind, ts := range t.events[id] {
+		kept := ts[:0]
+		for _, at := range ts {
+			if !at.Before(cutoff) {
+				kept = append(kept, at)
+			}
+		}
+		t.events[id][kind] = kept
+	}
+}
+
+// applyWindow adds/augments issues based on event counts in the sliding window, which
+// catches restart loops and OOM kills a point-in-time scan would miss.
+func (t *Tracker) applyWindow(id string, issues []types.Issue, info *types.ContainerInfo) []types.Issue {
+	byKind := t.events[id]
+	name := t.names[id]
+	if info != nil && info.Name != "" {
+		name = info.Name
+	}
+	subject := "container=" + id
+	window := t.window.String()
+
+	restarts := restartsInWindow(byKind["die"], byKind["start"])
+	if restarts > t.cfg.Rules.Restarts.Threshold {
+		idx := indexOf(issues, "RESTART_LOOP", subject)
+		if idx < 0 {
+			issues = append(issues, types.Issue{
+				RuleID:      "RESTART_LOOP",
+				Subject:     subject,
+				Severity:    "high",
+				Category:    "restarts",
+				Description: fmt.Sprintf("Container %s (%s) exited and started again %d times in the last %s", name, id, restarts, window),
+				Facts: map[string]interface{}{
+					"container_id":   id,
+					"container_name": name,
+					"threshold":      t.cfg.Rules.Restarts.Threshold,
+				},
+				Solutions: []string{
+					fmt.Sprintf("Check logs: 'docker logs %s'", id),
+					"Inspect container configuration for errors.",
+					"Check resource limits (CPU/memory) that might cause crashes.",
+				},
+			})
+			idx = len(issues) - 1
+		}
+		issues[idx].Facts["restarts_in_window"] = restarts
+		issues[idx].Facts["window"] = window
+	}
+
+	if ooms := len(byKind["oom"]); ooms > 0 && t.cfg.Rules.OOM.Enabled {
+		idx := indexOf(issues, "OOM_KILLED", subject)
+		if idx < 0 {
+			issues = append(issues, types.Issue{
+				RuleID:      "OOM_KILLED",
+				Subject:     subject,
+				Severity:    "high",
+				Category:    "oom",
+				Description: fmt.Sprintf("Container %s (%s) hit an out-of-memory kill %d time(s) in the last %s", name, id, ooms, window),
+				Facts: map[string]interface{}{
+					"container_id":   id,
+					"container_name": name,
+				},
+				Solutions: []string{
+					fmt.Sprintf("Check logs: 'docker logs %s'", id),
+					"Increase memory limit: 'docker update --memory <limit> " + id + "'",
+					"Check for memory leaks in the application.",
+				},
+			})
+			idx = len(issues) - 1
+		}
+		issues[idx].Facts["oom_events_in_window"] = ooms
+		issues[idx].Facts["window"] = window
+	}
+	return issues
+}
+
+// filterEmitted returns issues not yet emitted at their current severity, and forgets
+// previously emitted issues for the container that no longer apply so they can fire again.
+func (t *Tracker) filterEmitted(id string, issues []types.Issue) []types.Issue {
+	prefix := "container=" + id
+	current := map[string]bool{}
+	var out []types.Issue
+	for _, is := range issues {
+		fp := is.RuleID + ":" + is.Subject
+		current[fp] = true
+		if t.emitted[fp] == is.Severity {
+			continue
+		}
+		t.emitted[fp] = is.Severity
+		out = append(out, is)
+	}
+	for fp := range t.emitted {
+		if strings.HasSuffix(fp, ":"+prefix) && !current[fp] {
+			delete(t.emitted, fp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].RuleID < out[j].RuleID })
+	return out
+}
+
+// restartsInWindow counts the exits that were followed by a start of the same container.
+// A die alone (docker stop, a finished one-off job, a container removed by compose down)
+// is not a restart, and a manual `docker restart` is one die and one start.
+func restartsInWindow(dies, starts []time.Time) int {
+	n, j := 0, 0
+	for _, d := range dies {
+		for j < len(starts) && starts[j].Before(d) {
+			j++
+		}
+		if j == len(starts) {
+			break
+		}
+		n++
+		j++
+	}
+	return n
+}
+
+func indexOf(issues []types.Issue, ruleID, subject string) int {
+	for i := range issues {
+		if issues[i].RuleID == ruleID && issues[i].Subject == subject {
+			if issues[i].Facts == nil {
+				issues[i].Facts = map[string]interface{}{}
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,128 @@
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dashu-baba/docker-doctor/internal/config"
+	"github.com/dashu-baba/docker-doctor/internal/types"
+)
+
+func TestKindFromAction(t *testing.T) {
+	for action, want := range map[string][2]string{
+		"die":                      {"die", ""},
+		"start":                    {"start", ""},
+		"oom":                      {"oom", ""},
+		"health_status: unhealthy": {"health_status", "unhealthy"},
+	} {
+		kind, detail, ok := KindFromAction(action)
+		if !ok || kind != want[0] || detail != want[1] {
+			t.Fatalf("KindFromAction(%q) = %q, %q, %v", action, kind, detail, ok)
+		}
+	}
+	if _, _, ok := KindFromAction("stop"); ok {
+		t.Fatalf("expected stop to be ignored")
+	}
+}
+
+func TestTracker_RestartLoopInWindow(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{Restarts: config.RestartsRule{Threshold: 2}}}
+	tr := NewTracker(time.Minute, cfg)
+	info := &types.ContainerInfo{ID: "abc123", Name: "/app", Status: "Up", HealthStatus: "none"}
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	// cycle feeds an exit followed by the restart policy starting the container again.
+	cycle := func(at time.Time) []types.Issue {
+		got := tr.Observe(Event{ContainerID: "abc123", Kind: "die", Time: at}, info)
+		return append(got, tr.Observe(Event{ContainerID: "abc123", Kind: "start", Time: at.Add(100 * time.Millisecond)}, info)...)
+	}
+
+	var emitted []types.Issue
+	for i := 0; i < 3; i++ {
+		emitted = append(emitted, cycle(start.Add(time.Duration(i)*time.Second))...)
+	}
+	if len(emitted) != 1 || emitted[0].RuleID != "RESTART_LOOP" {
+		t.Fatalf("expected a single RESTART_LOOP emission, got %+v", emitted)
+	}
+	if emitted[0].Facts["restarts_in_window"] != 3 {
+		t.Fatalf("expected restarts_in_window=3, got %#v", emitted[0].Facts["restarts_in_window"])
+	}
+
+	// Still in the window: already emitted, so nothing new.
+	if got := cycle(start.Add(10 * time.Second)); len(got) != 0 {
+		t.Fatalf("expected no re-emission, got %+v", got)
+	}
+
+	// Window expired: the finding clears, and can fire again later.
+	if got := cycle(start.Add(5 * time.Minute)); len(got) != 0 {
+		t.Fatalf("expected no finding after window expiry, got %+v", got)
+	}
+	if n := tr.Counts(start.Add(5 * time.Minute))["abc123"]["die"]; n != 1 {
+		t.Fatalf("expected 1 die event in window, got %d", n)
+	}
+}
+
+func TestTracker_DockerRestartCountsOnce(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{Restarts: config.RestartsRule{Threshold: 2}}}
+	tr := NewTracker(time.Minute, cfg)
+	info := &types.ContainerInfo{ID: "abc123", Name: "/app", Status: "Up", HealthStatus: "none"}
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// `docker restart` emits die, start and restart.
+	restart := func(at time.Time) []types.Issue {
+		var got []types.Issue
+		for i, kind := range []string{"die", "start", "restart"} {
+			got = append(got, tr.Observe(Event{ContainerID: "abc123", Kind: kind, Time: at.Add(time.Duration(i) * time.Millisecond)}, info)...)
+		}
+		return got
+	}
+	var emitted []types.Issue
+	for i := 0; i < 2; i++ {
+		emitted = append(emitted, restart(start.Add(time.Duration(i)*time.Second))...)
+	}
+	if len(emitted) != 0 {
+		t.Fatalf("expected two restarts to stay under the threshold, got %+v", emitted)
+	}
+
+	emitted = restart(start.Add(2 * time.Second))
+	if len(emitted) != 1 || emitted[0].Facts["restarts_in_window"] != 3 {
+		t.Fatalf("expected RESTART_LOOP with restarts_in_window=3, got %+v", emitted)
+	}
+}
+
+func TestTracker_ExitWithoutStartIsNoRestart(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{Restarts: config.RestartsRule{Threshold: 2}}}
+	tr := NewTracker(time.Minute, cfg)
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// docker stop of several containers, or one-off jobs finishing: each dies once.
+	for i, id := range []string{"job1", "job1", "job1", "web"} {
+		info := &types.ContainerInfo{ID: id, Name: "/" + id, Status: "Exited (0)", HealthStatus: "none"}
+		if got := tr.Observe(Event{ContainerID: id, Kind: "die", Time: start.Add(time.Duration(i) * time.Second)}, info); len(got) != 0 {
+			t.Fatalf("expected no finding for exits without a start, got %+v", got)
+		}
+	}
+	if n := restartsInWindow([]time.Time{start, start.Add(time.Second)}, []time.Time{start.Add(2 * time.Second)}); n != 1 {
+		t.Fatalf("expected two exits and one start to be one restart, got %d", n)
+	}
+}
+
+func TestTracker_OOMAndHealthEmitted(t *testing.T) {
+	cfg := &config.Config{Rules: config.Rules{
+		Restarts:    config.RestartsRule{Threshold: 100},
+		OOM:         config.OOMRule{Enabled: true},
+		Healthcheck: config.HealthcheckRule{Enabled: true},
+	}}
+	tr := NewTracker(time.Minute, cfg)
+	now := time.Now()
+
+	got := tr.Observe(Event{ContainerID: "abc123", Name: "/app", Kind: "oom", Time: now}, nil)
+	if len(got) != 1 || got[0].RuleID != "OOM_KILLED" {
+		t.Fatalf("expected OOM_KILLED from event alone, got %+v", got)
+	}
+
+	unhealthy := &types.ContainerInfo{ID: "abc123", Name: "/app", Status: "Up", HealthStatus: "unhealthy", UnhealthySince: now.Add(-time.Minute)}
+	got = tr.Observe(Event{ContainerID: "abc123", Kind: "health_status", Detail: "unhealthy", Time: now}, unhealthy)
+	if len(got) != 1 || got[0].RuleID != "HEALTHCHECK_UNHEALTHY" {
+		t.Fatalf("expected HEALTHCHECK_UNHEALTHY, got %+v", got)
+	}
+}